@@ -0,0 +1,209 @@
+package sdp
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Address is value of Email or Phone field that is <email-address> or
+// <phone-number> with optional display name, see RFC 4566 Section 5.6.
+//
+// Both "j.doe@example.com (Jane Doe)" and "Jane Doe <j.doe@example.com>"
+// forms are supported, first one is used by default.
+type Address struct {
+	Address string
+	Name    string // optional display name, unquoted
+	// AngleBrackets selects "Name <address>" form, or "<address>" if
+	// Name is blank. Name is quoted if it has special characters, like
+	// "Doe, Jane" <j.doe@example.com>.
+	AngleBrackets bool
+}
+
+func (a Address) String() string {
+	return string(a.appendTo(nil))
+}
+
+func (a Address) appendTo(b []byte) []byte {
+	if a.AngleBrackets {
+		if len(a.Name) > 0 {
+			b = appendDisplayName(b, a.Name)
+			b = append(b, ' ')
+		}
+		b = append(b, '<')
+		b = append(b, a.Address...)
+		return append(b, '>')
+	}
+	b = append(b, a.Address...)
+	if len(a.Name) > 0 {
+		b = append(b, " ("...)
+		b = appendCommentName(b, a.Name)
+		b = appendRune(b, ')')
+	}
+	return b
+}
+
+// appendCommentName appends name to b, escaping parentheses and
+// backslashes as quoted pairs of RFC 5322 comment.
+func appendCommentName(b []byte, name string) []byte {
+	for i := 0; i < len(name); i++ {
+		switch name[i] {
+		case '(', ')', '\\':
+			b = append(b, '\\')
+		}
+		b = append(b, name[i])
+	}
+	return b
+}
+
+// displayNameSpecials are characters that require quoting of display
+// name, see "specials" of RFC 5322 Section 3.2.3.
+const displayNameSpecials = `()<>[]:;@\,"`
+
+// appendDisplayName appends name to b, as quoted string with escaped
+// quotes and backslashes if name has special characters.
+func appendDisplayName(b []byte, name string) []byte {
+	if !strings.ContainsAny(name, displayNameSpecials) && strings.TrimSpace(name) == name {
+		return append(b, name...)
+	}
+	b = append(b, '"')
+	for i := 0; i < len(name); i++ {
+		if name[i] == '"' || name[i] == '\\' {
+			b = append(b, '\\')
+		}
+		b = append(b, name[i])
+	}
+	return append(b, '"')
+}
+
+// unquoteDisplayName returns name without quotes and escaping if it is
+// quoted string, or name otherwise.
+func unquoteDisplayName(name []byte) []byte {
+	if len(name) < 2 || name[0] != '"' || name[len(name)-1] != '"' {
+		return name
+	}
+	return unescapeQuotedPairs(name[1 : len(name)-1])
+}
+
+// unescapeQuotedPairs returns name with quoted pairs like "\\(" replaced
+// by escaped characters.
+func unescapeQuotedPairs(name []byte) []byte {
+	if bytes.IndexByte(name, '\\') < 0 {
+		return name
+	}
+	unquoted := make([]byte, 0, len(name))
+	for i := 0; i < len(name); i++ {
+		if name[i] == '\\' && i+1 < len(name) {
+			i++
+		}
+		unquoted = append(unquoted, name[i])
+	}
+	return unquoted
+}
+
+// lastCommentStart returns index of last "(" in v that is not escaped
+// as quoted pair, or -1.
+func lastCommentStart(v []byte) int {
+	for i := len(v) - 1; i >= 0; i-- {
+		if v[i] != '(' {
+			continue
+		}
+		escapes := 0
+		for j := i - 1; j >= 0 && v[j] == '\\'; j-- {
+			escapes++
+		}
+		if escapes%2 == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// E164 returns phone number of a normalized to E.164 form like
+// "+16175556011" or false if Address is not an international phone
+// number.
+func (a Address) E164() (string, bool) {
+	return NormalizeE164(a.Address)
+}
+
+// maxE164Digits is maximum count of digits in E.164 number.
+const maxE164Digits = 15
+
+// NormalizeE164 removes visual separators (spaces, hyphens, dots and
+// parentheses) from international phone number like "+1 617 555-6011",
+// returning "+16175556011" or false if phone is not valid E.164 number.
+func NormalizeE164(phone string) (string, bool) {
+	if !strings.HasPrefix(phone, "+") {
+		return "", false
+	}
+	b := make([]byte, 1, len(phone))
+	b[0] = '+'
+	for i := 1; i < len(phone); i++ {
+		switch c := phone[i]; {
+		case c >= '0' && c <= '9':
+			b = append(b, c)
+		case c == ' ', c == '-', c == '.', c == '(', c == ')':
+			continue
+		default:
+			return "", false
+		}
+	}
+	if digits := len(b) - 1; digits == 0 || digits > maxE164Digits || b[1] == '0' {
+		return "", false
+	}
+	return string(b), true
+}
+
+// ParseAddress parses Email or Phone field value.
+func ParseAddress(v string) Address {
+	address, name, angle := splitAddress([]byte(v))
+	return Address{
+		Address:       string(address),
+		Name:          string(name),
+		AngleBrackets: angle,
+	}
+}
+
+// splitAddress returns address and display name of email or phone
+// field value v in "address (name)" or "name <address>" form, and
+// whether second form is used. Values that are not in any of those
+// forms are returned as address.
+func splitAddress(v []byte) (address, name []byte, angle bool) {
+	v = bytes.TrimSpace(v)
+	if len(v) == 0 {
+		return v, nil, false
+	}
+	switch v[len(v)-1] {
+	case '>':
+		// Jane Doe <j.doe@example.com>
+		start := bytes.LastIndexByte(v, '<')
+		if start < 0 {
+			break
+		}
+		name = unquoteDisplayName(bytes.TrimSpace(v[:start]))
+		return bytes.TrimSpace(v[start+1 : len(v)-1]), name, true
+	case ')':
+		// j.doe@example.com (Jane Doe)
+		// Last parenthesis, because phone number can contain them.
+		start := lastCommentStart(v)
+		if start <= 0 {
+			break
+		}
+		name = unescapeQuotedPairs(v[start+1 : len(v)-1])
+		return bytes.TrimSpace(v[:start]), name, false
+	}
+	return v, nil, false
+}
+
+// AddEmailAddress appends Email field with address to Session.
+func (s Session) AddEmailAddress(a Address) Session {
+	line := s.getLine(TypeEmail)
+	line.Value = a.appendTo(line.Value)
+	return append(s, line)
+}
+
+// AddPhoneAddress appends Phone field with address to Session.
+func (s Session) AddPhoneAddress(a Address) Session {
+	line := s.getLine(TypePhone)
+	line.Value = a.appendTo(line.Value)
+	return append(s, line)
+}
@@ -0,0 +1,152 @@
+package sdp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseAddress(t *testing.T) {
+	for _, tc := range []struct {
+		in  string
+		out Address
+	}{
+		{"j.doe@example.com", Address{Address: "j.doe@example.com"}},
+		{"j.doe@example.com (Jane Doe)", Address{Address: "j.doe@example.com", Name: "Jane Doe"}},
+		{"Jane Doe <j.doe@example.com>", Address{Address: "j.doe@example.com", Name: "Jane Doe", AngleBrackets: true}},
+		{`"Doe, Jane" <j.doe@example.com>`, Address{Address: "j.doe@example.com", Name: "Doe, Jane", AngleBrackets: true}},
+		{"<j.doe@example.com>", Address{Address: "j.doe@example.com", AngleBrackets: true}},
+		{`"Jane \"JD\" Doe" <j.doe@example.com>`, Address{Address: "j.doe@example.com", Name: `Jane "JD" Doe`, AngleBrackets: true}},
+		{`j.doe@example.com (Jane \(JD\) Doe)`, Address{Address: "j.doe@example.com", Name: "Jane (JD) Doe"}},
+		{"+1 617 555-6011", Address{Address: "+1 617 555-6011"}},
+		{"+1 (617) 555-6011 (Jane Doe)", Address{Address: "+1 (617) 555-6011", Name: "Jane Doe"}},
+		{"+1 (617) 555-6011", Address{Address: "+1 (617) 555-6011"}},
+		{"(Jane Doe)", Address{Address: "(Jane Doe)"}},
+		{"j.doe@example.com>", Address{Address: "j.doe@example.com>"}},
+		{" ", Address{}},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			if a := ParseAddress(tc.in); a != tc.out {
+				t.Errorf("%+v != %+v", a, tc.out)
+			}
+		})
+	}
+}
+
+func TestAddress_String(t *testing.T) {
+	for _, tc := range []struct {
+		in  Address
+		out string
+	}{
+		{Address{Address: "j.doe@example.com"}, "j.doe@example.com"},
+		{Address{Address: "j.doe@example.com", AngleBrackets: true}, "<j.doe@example.com>"},
+		{Address{Address: "j.doe@example.com", Name: "Jane Doe"}, "j.doe@example.com (Jane Doe)"},
+		{Address{Address: "j.doe@example.com", Name: `Jane (JD) \ Doe`}, `j.doe@example.com (Jane \(JD\) \\ Doe)`},
+		{Address{Address: "j.doe@example.com", Name: "Jane Doe", AngleBrackets: true}, "Jane Doe <j.doe@example.com>"},
+		{Address{Address: "j.doe@example.com", Name: "Doe, Jane", AngleBrackets: true}, `"Doe, Jane" <j.doe@example.com>`},
+		{Address{Address: "j.doe@example.com", Name: `Jane "JD" \ Doe`, AngleBrackets: true}, `"Jane \"JD\" \\ Doe" <j.doe@example.com>`},
+	} {
+		t.Run(tc.out, func(t *testing.T) {
+			if s := tc.in.String(); s != tc.out {
+				t.Errorf("%q != %q", s, tc.out)
+			}
+		})
+	}
+}
+
+func TestAddress_RoundTrip(t *testing.T) {
+	for _, in := range []string{
+		`"Name, Jr." <a@b>`,
+		`<a@b>`,
+		`"Jane \"JD\" Doe" <j.doe@example.com>`,
+		"Jane Doe <j.doe@example.com>",
+		"j.doe@example.com (Jane Doe)",
+		`j.doe@example.com (Jane \(JD\) Doe)`,
+		`+1 (617) 555-6011 (Jane \\ Doe\))`,
+		"j.doe@example.com",
+	} {
+		t.Run(in, func(t *testing.T) {
+			a := ParseAddress(in)
+			if out := a.String(); out != in {
+				t.Errorf("%q != %q", out, in)
+			}
+			s := new(Session).AddEmailAddress(a)
+			m := &Message{Origin: Origin{Username: "-", Address: "127.0.0.1"}, Name: "-", Emails: []Address{a}}
+			b, err := m.MarshalText()
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := Decode(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(decoded.Emails) != 1 || decoded.Emails[0] != a {
+				t.Errorf("%+v != %+v", decoded.Emails, a)
+			}
+			if string(s[0].Value) != in {
+				t.Errorf("%q != %q", s[0].Value, in)
+			}
+		})
+	}
+}
+
+func TestNormalizeE164(t *testing.T) {
+	for _, tc := range []struct {
+		in  string
+		out string
+		ok  bool
+	}{
+		{"+1 617 555-6011", "+16175556011", true},
+		{"+1 (617) 555.6011", "+16175556011", true},
+		{"+442079460018", "+442079460018", true},
+		{"617 555-6011", "", false},
+		{"+1 617 CALL-NOW", "", false},
+		{"+0 617 555-6011", "", false},
+		{"+", "", false},
+		{"+1234567890123456", "", false},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			out, ok := NormalizeE164(tc.in)
+			if out != tc.out || ok != tc.ok {
+				t.Errorf("%q, %v != %q, %v", out, ok, tc.out, tc.ok)
+			}
+		})
+	}
+}
+
+func TestDecoder_Addresses(t *testing.T) {
+	data := loadData(t, "sdp_session_ex_addresses", testCRNL)
+	m, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	emails := []Address{
+		{Address: "j.doe@example.com", Name: "Jane Doe"},
+		{Address: "john.doe@example.com", Name: "John Doe", AngleBrackets: true},
+		{Address: "mmusic@example.com"},
+	}
+	if len(m.Emails) != len(emails) {
+		t.Fatalf("unexpected emails %v", m.Emails)
+	}
+	for i := range emails {
+		if m.Emails[i] != emails[i] {
+			t.Errorf("emails[%d]: %+v != %+v", i, m.Emails[i], emails[i])
+		}
+	}
+	if len(m.Phones) != 2 {
+		t.Fatalf("unexpected phones %v", m.Phones)
+	}
+	for i, expected := range []string{"+16175556011", "+442079460018"} {
+		if phone, ok := m.Phones[i].E164(); !ok || phone != expected {
+			t.Errorf("phones[%d]: %q != %q", i, phone, expected)
+		}
+	}
+	if m.Phones[0].Name != "Jane Doe" {
+		t.Errorf("unexpected name %q", m.Phones[0].Name)
+	}
+	t.Run("Encode", func(t *testing.T) {
+		out := m.Append(nil).AppendTo(nil)
+		if !bytes.Equal(bytes.TrimSpace(out), bytes.TrimSpace(data)) {
+			t.Errorf("%s != %s", out, data)
+		}
+	})
+}
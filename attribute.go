@@ -0,0 +1,136 @@
+package sdp
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// AttributeCodec decodes and encodes values of attribute, like
+// "96 opus/48000/2" of "a=rtpmap:96 opus/48000/2".
+type AttributeCodec interface {
+	// Decode decodes attribute value to v, which is pointer to type
+	// that is supported by codec. Attributes.Decode passes copy of
+	// value that does not share memory with decoded message, so
+	// decoded values can reference it.
+	Decode(value string, v interface{}) error
+	// Encode validates v, which is value or pointer of type that is
+	// supported by codec, and returns its attribute value.
+	Encode(v interface{}) (string, error)
+}
+
+// Possible errors of Attributes.Decode and Attributes.Encode.
+var (
+	ErrUnknownAttribute = errors.New("no codec registered for attribute")
+	ErrNoAttribute      = errors.New("attribute not found")
+	ErrAttributeType    = errors.New("type is not supported by attribute codec")
+)
+
+var (
+	attributeCodecsMux sync.RWMutex
+	attributeCodecs    = map[string]AttributeCodec{
+		AttributeRTPMap:       RTPMapCodec{},
+		AttributeFMTP:         FMTPCodec{},
+		AttributeRTCPFeedback: RTCPFeedbackCodec{},
+		AttributeCandidate:    CandidateCodec{},
+	}
+)
+
+// RegisterAttribute registers c as AttributeCodec for attribute key,
+// replacing previously registered one.
+func RegisterAttribute(key string, c AttributeCodec) {
+	attributeCodecsMux.Lock()
+	attributeCodecs[key] = c
+	attributeCodecsMux.Unlock()
+}
+
+// LookupAttribute returns AttributeCodec that is registered for
+// attribute key.
+func LookupAttribute(key string) (AttributeCodec, bool) {
+	attributeCodecsMux.RLock()
+	c, ok := attributeCodecs[key]
+	attributeCodecsMux.RUnlock()
+	return c, ok
+}
+
+func lookupAttribute(key string) (AttributeCodec, error) {
+	c, ok := LookupAttribute(key)
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownAttribute, "%q", key)
+	}
+	return c, nil
+}
+
+// Decode decodes value of first attribute with key to v using codec
+// that is registered for key. If v is pointer to slice, values of all
+// attributes with key are decoded and appended to it.
+func (a Attributes) Decode(key string, v interface{}) error {
+	c, err := lookupAttribute(key)
+	if err != nil {
+		return err
+	}
+	if s := reflect.ValueOf(v); s.Kind() == reflect.Ptr && s.Elem().Kind() == reflect.Slice {
+		s = s.Elem()
+		for _, attr := range a {
+			if attr.Key != key {
+				continue
+			}
+			e := reflect.New(s.Type().Elem())
+			if err = c.Decode(cloneString(attr.Value), e.Interface()); err != nil {
+				return errors.Wrapf(err, "failed to decode %s", key)
+			}
+			s.Set(reflect.Append(s, e.Elem()))
+		}
+		return nil
+	}
+	for _, attr := range a {
+		if attr.Key != key {
+			continue
+		}
+		// Attributes can reference memory of session if decoded
+		// with DecoderOptions.ZeroCopy.
+		if err = c.Decode(cloneString(attr.Value), v); err != nil {
+			return errors.Wrapf(err, "failed to decode %s", key)
+		}
+		return nil
+	}
+	return errors.Wrapf(ErrNoAttribute, "%q", key)
+}
+
+// Encode appends attribute with key and value of v that is encoded by
+// codec registered for key, returning new Attributes. If v is slice,
+// attribute is appended for every element. On error a is returned
+// unchanged, so no attributes are appended.
+func (a Attributes) Encode(key string, v interface{}) (Attributes, error) {
+	c, err := lookupAttribute(key)
+	if err != nil {
+		return a, err
+	}
+	if s := reflect.ValueOf(v); s.Kind() == reflect.Slice {
+		encoded := a
+		for i := 0; i < s.Len(); i++ {
+			if encoded, err = encoded.encode(c, key, s.Index(i).Interface()); err != nil {
+				return a, err
+			}
+		}
+		return encoded, nil
+	}
+	return a.encode(c, key, v)
+}
+
+func (a Attributes) encode(c AttributeCodec, key string, v interface{}) (Attributes, error) {
+	value, err := c.Encode(v)
+	if err == nil {
+		err = validateByteString(key, value)
+	}
+	if err != nil {
+		return a, errors.Wrapf(err, "failed to encode %s", key)
+	}
+	return addAttribute(a, key, value), nil
+}
+
+// unexpectedType returns ErrAttributeType for v.
+func unexpectedType(v interface{}) error {
+	return errors.Wrapf(ErrAttributeType, "%T", v)
+}
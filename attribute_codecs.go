@@ -0,0 +1,367 @@
+package sdp
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Attributes with built-in AttributeCodec.
+const (
+	AttributeRTPMap       = "rtpmap"    // RFC 8866 Section 6.6, RTPMap
+	AttributeFMTP         = "fmtp"      // RFC 8866 Section 6.15, FMTP
+	AttributeRTCPFeedback = "rtcp-fb"   // RFC 4585 Section 4.2, RTCPFeedback
+	AttributeCandidate    = "candidate" // RFC 8839 Section 5.1, Candidate
+)
+
+func invalidSyntax(name, v string) error {
+	return errors.Wrapf(ErrInvalidSyntax, "%s %q", name, v)
+}
+
+func parseAttributeInt(name, v string, bitSize int) (int, error) {
+	n, err := strconv.ParseUint(v, 10, bitSize)
+	if err != nil {
+		return 0, errors.Wrapf(ErrInvalidNumber, "%s %q", name, v)
+	}
+	return int(n), nil
+}
+
+// RTPMap is value of "a=rtpmap:<payload type> <encoding name>/<clock
+// rate>[/<encoding parameters>]" attribute.
+type RTPMap struct {
+	PayloadType int
+	Encoding    string
+	ClockRate   int
+	Channels    int // <encoding parameters>, zero if omitted
+}
+
+// RTPMapCodec is AttributeCodec for RTPMap.
+type RTPMapCodec struct{}
+
+// Decode implements AttributeCodec.
+func (RTPMapCodec) Decode(value string, v interface{}) error {
+	r, ok := v.(*RTPMap)
+	if !ok {
+		return unexpectedType(v)
+	}
+	p := strings.Fields(value)
+	if len(p) != 2 {
+		return invalidSyntax("rtpmap", value)
+	}
+	e := strings.Split(p[1], "/")
+	if len(e) < 2 || len(e) > 3 || e[0] == "" {
+		return invalidSyntax("encoding", p[1])
+	}
+	var (
+		decoded = RTPMap{Encoding: e[0]}
+		err     error
+	)
+	if decoded.PayloadType, err = parseAttributeInt("payload type", p[0], 7); err != nil {
+		return err
+	}
+	if decoded.ClockRate, err = parseAttributeInt("clock rate", e[1], 32); err != nil {
+		return err
+	}
+	if len(e) == 3 {
+		if decoded.Channels, err = parseAttributeInt("channels", e[2], 16); err != nil {
+			return err
+		}
+	}
+	*r = decoded
+	return nil
+}
+
+// Encode implements AttributeCodec.
+func (RTPMapCodec) Encode(v interface{}) (string, error) {
+	var r RTPMap
+	switch value := v.(type) {
+	case RTPMap:
+		r = value
+	case *RTPMap:
+		r = *value
+	default:
+		return "", unexpectedType(v)
+	}
+	if r.PayloadType < 0 || r.PayloadType > 127 {
+		return "", &ValidationError{Field: "PayloadType", Err: ErrInvalidNumber}
+	}
+	if r.ClockRate <= 0 || r.Channels < 0 {
+		return "", &ValidationError{Field: "ClockRate", Err: ErrInvalidNumber}
+	}
+	if err := validateToken("Encoding", r.Encoding); err != nil {
+		return "", err
+	}
+	b := make([]byte, 0, 64)
+	b = strconv.AppendInt(b, int64(r.PayloadType), 10)
+	b = append(b, ' ')
+	b = append(b, r.Encoding...)
+	b = append(b, '/')
+	b = strconv.AppendInt(b, int64(r.ClockRate), 10)
+	if r.Channels > 0 {
+		b = append(b, '/')
+		b = strconv.AppendInt(b, int64(r.Channels), 10)
+	}
+	return string(b), nil
+}
+
+// FMTP is value of "a=fmtp:<format> <format specific parameters>"
+// attribute.
+type FMTP struct {
+	Format     string
+	Parameters string
+}
+
+// Params returns ";" separated "<name>=<value>" parameters, like
+// "minptime=10;useinbandfec=1". Parameters without "=" have blank value.
+func (f FMTP) Params() map[string]string {
+	params := make(map[string]string)
+	for _, p := range strings.Split(f.Parameters, ";") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		name, value := p, ""
+		if i := strings.IndexByte(p, '='); i >= 0 {
+			name, value = p[:i], p[i+1:]
+		}
+		params[name] = value
+	}
+	return params
+}
+
+// FMTPCodec is AttributeCodec for FMTP.
+type FMTPCodec struct{}
+
+// Decode implements AttributeCodec.
+func (FMTPCodec) Decode(value string, v interface{}) error {
+	f, ok := v.(*FMTP)
+	if !ok {
+		return unexpectedType(v)
+	}
+	i := strings.IndexByte(value, ' ')
+	if i <= 0 || i == len(value)-1 {
+		return invalidSyntax("fmtp", value)
+	}
+	*f = FMTP{
+		Format:     value[:i],
+		Parameters: strings.TrimSpace(value[i+1:]),
+	}
+	return nil
+}
+
+// Encode implements AttributeCodec.
+func (FMTPCodec) Encode(v interface{}) (string, error) {
+	var f FMTP
+	switch value := v.(type) {
+	case FMTP:
+		f = value
+	case *FMTP:
+		f = *value
+	default:
+		return "", unexpectedType(v)
+	}
+	if err := firstError(
+		validateToken("Format", f.Format),
+		validateRequired("Parameters", f.Parameters),
+	); err != nil {
+		return "", err
+	}
+	return f.Format + " " + f.Parameters, nil
+}
+
+// RTCPFeedback is value of "a=rtcp-fb:<payload type> <type> [<parameter>]"
+// attribute.
+type RTCPFeedback struct {
+	PayloadType string // "*" for all payload types
+	Type        string // like "nack", "ccm" or "trr-int"
+	Parameter   string // like "pli" or "fir", blank if omitted
+}
+
+// RTCPFeedbackCodec is AttributeCodec for RTCPFeedback.
+type RTCPFeedbackCodec struct{}
+
+// Decode implements AttributeCodec.
+func (RTCPFeedbackCodec) Decode(value string, v interface{}) error {
+	f, ok := v.(*RTCPFeedback)
+	if !ok {
+		return unexpectedType(v)
+	}
+	p := strings.SplitN(strings.TrimSpace(value), " ", 3)
+	if len(p) < 2 || p[0] == "" || p[1] == "" {
+		return invalidSyntax("rtcp-fb", value)
+	}
+	decoded := RTCPFeedback{PayloadType: p[0], Type: p[1]}
+	if len(p) == 3 {
+		decoded.Parameter = strings.TrimSpace(p[2])
+	}
+	*f = decoded
+	return nil
+}
+
+// Encode implements AttributeCodec.
+func (RTCPFeedbackCodec) Encode(v interface{}) (string, error) {
+	var f RTCPFeedback
+	switch value := v.(type) {
+	case RTCPFeedback:
+		f = value
+	case *RTCPFeedback:
+		f = *value
+	default:
+		return "", unexpectedType(v)
+	}
+	if err := firstError(
+		validateToken("PayloadType", f.PayloadType),
+		validateToken("Type", f.Type),
+		validateByteString("Parameter", f.Parameter),
+	); err != nil {
+		return "", err
+	}
+	s := f.PayloadType + " " + f.Type
+	if f.Parameter != "" {
+		s += " " + f.Parameter
+	}
+	return s, nil
+}
+
+// Candidate is value of ICE "a=candidate" attribute.
+type Candidate struct {
+	Foundation     string
+	Component      int
+	Transport      string // like "UDP"
+	Priority       uint32
+	Address        string // IP address or FQDN
+	Port           int
+	Type           string // "host", "srflx", "prflx" or "relay"
+	RelatedAddress string // "raddr", blank if omitted
+	RelatedPort    int    // "rport", zero if omitted
+	Extensions     Attributes
+}
+
+// validComponent reports whether <component-id> is in [1, 256] range,
+// see RFC 8839 Section 5.1.
+func validComponent(id int) bool {
+	return id >= 1 && id <= 256
+}
+
+// CandidateCodec is AttributeCodec for Candidate.
+type CandidateCodec struct{}
+
+// Decode implements AttributeCodec.
+func (CandidateCodec) Decode(value string, v interface{}) error {
+	c, ok := v.(*Candidate)
+	if !ok {
+		return unexpectedType(v)
+	}
+	// <foundation> <component-id> <transport> <priority>
+	// <connection-address> <port> typ <cand-type>
+	// [raddr <connection-address>] [rport <port>] *(<name> <value>)
+	p := strings.Fields(value)
+	if len(p) < 8 || len(p)%2 != 0 || p[6] != "typ" {
+		return invalidSyntax("candidate", value)
+	}
+	var (
+		decoded = Candidate{
+			Foundation: p[0],
+			Transport:  p[2],
+			Address:    p[4],
+			Type:       p[7],
+		}
+		priority uint64
+		err      error
+	)
+	if decoded.Component, err = parseAttributeInt("component", p[1], 16); err != nil {
+		return err
+	}
+	if !validComponent(decoded.Component) {
+		return errors.Wrapf(ErrInvalidNumber, "component %q", p[1])
+	}
+	if priority, err = strconv.ParseUint(p[3], 10, 32); err != nil {
+		return errors.Wrapf(ErrInvalidNumber, "priority %q", p[3])
+	}
+	decoded.Priority = uint32(priority)
+	if decoded.Port, err = parseAttributeInt("port", p[5], 16); err != nil {
+		return err
+	}
+	for i := 8; i < len(p); i += 2 {
+		switch p[i] {
+		case "raddr":
+			decoded.RelatedAddress = p[i+1]
+		case "rport":
+			if decoded.RelatedPort, err = parseAttributeInt("rport", p[i+1], 16); err != nil {
+				return err
+			}
+		default:
+			decoded.Extensions = addAttribute(decoded.Extensions, p[i], p[i+1])
+		}
+	}
+	*c = decoded
+	return nil
+}
+
+// Encode implements AttributeCodec.
+func (CandidateCodec) Encode(v interface{}) (string, error) {
+	var c Candidate
+	switch value := v.(type) {
+	case Candidate:
+		c = value
+	case *Candidate:
+		c = *value
+	default:
+		return "", unexpectedType(v)
+	}
+	err := firstError(
+		validateToken("Foundation", c.Foundation),
+		validateToken("Transport", c.Transport),
+		validateToken("Address", c.Address),
+		validateToken("Type", c.Type),
+		validateOptionalToken("RelatedAddress", c.RelatedAddress),
+	)
+	if err != nil {
+		return "", err
+	}
+	for i, e := range c.Extensions {
+		field := "Extensions[" + strconv.Itoa(i) + "]"
+		if err = firstError(
+			validateToken(field+".Key", e.Key),
+			validateToken(field+".Value", e.Value),
+		); err != nil {
+			return "", err
+		}
+	}
+	if !validComponent(c.Component) {
+		return "", &ValidationError{Field: "Component", Err: ErrInvalidNumber}
+	}
+	if c.Port < 0 || c.Port > 65535 || c.RelatedPort < 0 || c.RelatedPort > 65535 {
+		return "", &ValidationError{Field: "Port", Err: ErrInvalidNumber}
+	}
+	b := make([]byte, 0, 128)
+	b = append(b, c.Foundation...)
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, int64(c.Component), 10)
+	b = append(b, ' ')
+	b = append(b, c.Transport...)
+	b = append(b, ' ')
+	b = strconv.AppendUint(b, uint64(c.Priority), 10)
+	b = append(b, ' ')
+	b = append(b, c.Address...)
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, int64(c.Port), 10)
+	b = append(b, " typ "...)
+	b = append(b, c.Type...)
+	if c.RelatedAddress != "" {
+		b = append(b, " raddr "...)
+		b = append(b, c.RelatedAddress...)
+	}
+	if c.RelatedPort != 0 || c.RelatedAddress != "" {
+		b = append(b, " rport "...)
+		b = strconv.AppendInt(b, int64(c.RelatedPort), 10)
+	}
+	for _, e := range c.Extensions {
+		b = append(b, ' ')
+		b = append(b, e.Key...)
+		b = append(b, ' ')
+		b = append(b, e.Value...)
+	}
+	return string(b), nil
+}
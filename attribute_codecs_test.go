@@ -0,0 +1,122 @@
+package sdp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestAttributeCodecs(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		codec AttributeCodec
+		value string
+		v     interface{}
+		empty interface{}
+	}{
+		{
+			name: "RTPMap", codec: RTPMapCodec{},
+			value: "96 opus/48000/2",
+			v:     &RTPMap{PayloadType: 96, Encoding: "opus", ClockRate: 48000, Channels: 2},
+			empty: new(RTPMap),
+		},
+		{
+			name: "RTPMapNoChannels", codec: RTPMapCodec{},
+			value: "0 PCMU/8000",
+			v:     &RTPMap{PayloadType: 0, Encoding: "PCMU", ClockRate: 8000},
+			empty: new(RTPMap),
+		},
+		{
+			name: "FMTP", codec: FMTPCodec{},
+			value: "111 minptime=10;useinbandfec=1",
+			v:     &FMTP{Format: "111", Parameters: "minptime=10;useinbandfec=1"},
+			empty: new(FMTP),
+		},
+		{
+			name: "RTCPFeedback", codec: RTCPFeedbackCodec{},
+			value: "* trr-int 100",
+			v:     &RTCPFeedback{PayloadType: "*", Type: "trr-int", Parameter: "100"},
+			empty: new(RTCPFeedback),
+		},
+		{
+			name: "RTCPFeedbackNoParameter", codec: RTCPFeedbackCodec{},
+			value: "96 goog-remb",
+			v:     &RTCPFeedback{PayloadType: "96", Type: "goog-remb"},
+			empty: new(RTCPFeedback),
+		},
+		{
+			name: "Candidate", codec: CandidateCodec{},
+			value: "842163049 1 udp 1677729535 91.225.236.99 56024 typ srflx raddr 10.1.22.220 rport 56024 generation 0",
+			v: &Candidate{
+				Foundation: "842163049", Component: 1, Transport: "udp", Priority: 1677729535,
+				Address: "91.225.236.99", Port: 56024, Type: "srflx",
+				RelatedAddress: "10.1.22.220", RelatedPort: 56024,
+				Extensions: Attributes{{Key: "generation", Value: "0"}},
+			},
+			empty: new(Candidate),
+		},
+		{
+			name: "CandidateHost", codec: CandidateCodec{},
+			value: "1 1 UDP 2130706431 10.0.1.1 8998 typ host",
+			v: &Candidate{
+				Foundation: "1", Component: 1, Transport: "UDP", Priority: 2130706431,
+				Address: "10.0.1.1", Port: 8998, Type: "host",
+			},
+			empty: new(Candidate),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.codec.Decode(tc.value, tc.empty); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tc.empty, tc.v) {
+				t.Errorf("%+v != %+v", tc.empty, tc.v)
+			}
+			value, err := tc.codec.Encode(tc.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value != tc.value {
+				t.Errorf("%q != %q", value, tc.value)
+			}
+		})
+	}
+}
+
+func TestAttributeCodecs_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		codec AttributeCodec
+		value string
+		v     interface{}
+		err   error
+	}{
+		{"RTPMapSyntax", RTPMapCodec{}, "96", new(RTPMap), ErrInvalidSyntax},
+		{"RTPMapEncoding", RTPMapCodec{}, "96 opus", new(RTPMap), ErrInvalidSyntax},
+		{"RTPMapPayloadType", RTPMapCodec{}, "128 opus/48000", new(RTPMap), ErrInvalidNumber},
+		{"RTPMapClockRate", RTPMapCodec{}, "96 opus/x", new(RTPMap), ErrInvalidNumber},
+		{"RTPMapType", RTPMapCodec{}, "96 opus/48000", new(FMTP), ErrAttributeType},
+		{"FMTPSyntax", FMTPCodec{}, "111", new(FMTP), ErrInvalidSyntax},
+		{"RTCPFeedbackSyntax", RTCPFeedbackCodec{}, "96", new(RTCPFeedback), ErrInvalidSyntax},
+		{"CandidateSyntax", CandidateCodec{}, "1 1 UDP 1 10.0.1.1 8998 host", new(Candidate), ErrInvalidSyntax},
+		{"CandidatePort", CandidateCodec{}, "1 1 UDP 1 10.0.1.1 x typ host", new(Candidate), ErrInvalidNumber},
+		{"CandidatePriority", CandidateCodec{}, "1 1 UDP -1 10.0.1.1 1 typ host", new(Candidate), ErrInvalidNumber},
+		{"CandidateComponentZero", CandidateCodec{}, "1 0 UDP 1 10.0.1.1 1 typ host", new(Candidate), ErrInvalidNumber},
+		{"CandidateComponent", CandidateCodec{}, "1 257 UDP 1 10.0.1.1 1 typ host", new(Candidate), ErrInvalidNumber},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.codec.Decode(tc.value, tc.v); !errors.Is(err, tc.err) {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestFMTP_Params(t *testing.T) {
+	f := FMTP{Format: "111", Parameters: "minptime=10; useinbandfec=1;0-15"}
+	expected := map[string]string{"minptime": "10", "useinbandfec": "1", "0-15": ""}
+	if p := f.Params(); !reflect.DeepEqual(p, expected) {
+		t.Error(p, "!=", expected)
+	}
+}
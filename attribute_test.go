@@ -0,0 +1,186 @@
+package sdp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+type upperCodec struct{}
+
+func (upperCodec) Decode(value string, v interface{}) error {
+	s, ok := v.(*string)
+	if !ok {
+		return unexpectedType(v)
+	}
+	*s = strings.ToUpper(value)
+	return nil
+}
+
+func (upperCodec) Encode(v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", unexpectedType(v)
+	}
+	return strings.ToLower(s), nil
+}
+
+func TestRegisterAttribute(t *testing.T) {
+	if _, ok := LookupAttribute("x-upper"); ok {
+		t.Fatal("should not be registered")
+	}
+	RegisterAttribute("x-upper", upperCodec{})
+	defer func() {
+		attributeCodecsMux.Lock()
+		delete(attributeCodecs, "x-upper")
+		attributeCodecsMux.Unlock()
+	}()
+	a, err := Attributes{}.Encode("x-upper", "Value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 1 || a[0] != (Attribute{Key: "x-upper", Value: "value"}) {
+		t.Fatal("unexpected", a)
+	}
+	var s string
+	if err = a.Decode("x-upper", &s); err != nil {
+		t.Fatal(err)
+	}
+	if s != "VALUE" {
+		t.Error(s, "!=", "VALUE")
+	}
+	if err = a.Decode("x-upper", new(int)); !errors.Is(err, ErrAttributeType) {
+		t.Error("unexpected error", err)
+	}
+	if _, err = a.Encode("x-upper", "a\r\nb"); !errors.Is(err, ErrInvalidByteString) {
+		t.Error("unexpected error", err)
+	}
+}
+
+func TestAttributes_Decode(t *testing.T) {
+	m, err := Decode(loadData(t, "spd_session_ex_webrtc1", testNL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := m.Medias[0].Attributes
+	var rtpMap RTPMap
+	if err = a.Decode(AttributeRTPMap, &rtpMap); err != nil {
+		t.Fatal(err)
+	}
+	if rtpMap != (RTPMap{PayloadType: 127, Encoding: "google-data", ClockRate: 90000}) {
+		t.Error("unexpected rtpmap", rtpMap)
+	}
+	var candidates []Candidate
+	if err = a.Decode(AttributeCandidate, &candidates); err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 6 {
+		t.Fatal("unexpected candidates count", len(candidates))
+	}
+	if c := candidates[4]; c.Type != "srflx" || c.RelatedAddress != "10.1.22.220" || c.RelatedPort != 51941 {
+		t.Error("unexpected candidate", c)
+	}
+	t.Run("NotFound", func(t *testing.T) {
+		if err := a.Decode(AttributeFMTP, new(FMTP)); !errors.Is(err, ErrNoAttribute) {
+			t.Error("unexpected error", err)
+		}
+	})
+	t.Run("Unknown", func(t *testing.T) {
+		if err := a.Decode("ice-ufrag", new(string)); !errors.Is(err, ErrUnknownAttribute) {
+			t.Error("unexpected error", err)
+		}
+	})
+	t.Run("ZeroCopy", func(t *testing.T) {
+		tData := loadData(t, "spd_session_ex_webrtc1", testNL)
+		s, err := DecodeSession(tData, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := NewDecoderWithOptions(s, DecoderOptions{ZeroCopy: true})
+		m := new(Message)
+		if err = d.Decode(m); err != nil {
+			t.Fatal(err)
+		}
+		var (
+			rtpMap     RTPMap
+			candidates []Candidate
+		)
+		if err = m.Medias[0].Attributes.Decode(AttributeRTPMap, &rtpMap); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.Medias[0].Attributes.Decode(AttributeCandidate, &candidates); err != nil {
+			t.Fatal(err)
+		}
+		// Corrupting session that is referenced by message.
+		for i := range s {
+			for j := range s[i].Value {
+				s[i].Value[j] = 'x'
+			}
+		}
+		if rtpMap.Encoding != "google-data" {
+			t.Error("rtpmap corrupted after session mutation", rtpMap)
+		}
+		if c := candidates[4]; c.Type != "srflx" || c.RelatedAddress != "10.1.22.220" {
+			t.Error("candidate corrupted after session mutation", c)
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		a := Attributes{{Key: AttributeRTPMap, Value: "x opus/48000"}}
+		if err := a.Decode(AttributeRTPMap, new(RTPMap)); !errors.Is(err, ErrInvalidNumber) {
+			t.Error("unexpected error", err)
+		}
+	})
+}
+
+func TestAttributes_Encode(t *testing.T) {
+	a, err := Attributes{}.Encode(AttributeRTCPFeedback, []RTCPFeedback{
+		{PayloadType: "96", Type: "nack"},
+		{PayloadType: "96", Type: "nack", Parameter: "pli"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Attributes{
+		{Key: "rtcp-fb", Value: "96 nack"},
+		{Key: "rtcp-fb", Value: "96 nack pli"},
+	}
+	if !reflect.DeepEqual(a, expected) {
+		t.Error(a, "!=", expected)
+	}
+	if _, err = a.Encode(AttributeRTPMap, RTPMap{PayloadType: 96, ClockRate: 90000}); !errors.Is(err, ErrMissingField) {
+		t.Error("unexpected error", err)
+	}
+	if _, err = a.Encode(AttributeRTPMap, FMTP{}); !errors.Is(err, ErrAttributeType) {
+		t.Error("unexpected error", err)
+	}
+	t.Run("Partial", func(t *testing.T) {
+		out, err := a.Encode(AttributeRTPMap, []RTPMap{
+			{PayloadType: 96, Encoding: "VP8", ClockRate: 90000},
+			{PayloadType: 97, ClockRate: 90000},
+		})
+		if !errors.Is(err, ErrMissingField) {
+			t.Fatal("unexpected error", err)
+		}
+		if !reflect.DeepEqual(out, expected) {
+			t.Error("attributes should not be changed on error:", out)
+		}
+	})
+	t.Run("CandidateComponent", func(t *testing.T) {
+		c := Candidate{
+			Foundation: "1", Transport: "UDP", Priority: 1,
+			Address: "10.0.1.1", Port: 8998, Type: "host",
+		}
+		for _, component := range []int{0, 257} {
+			c.Component = component
+			if _, err := a.Encode(AttributeCandidate, c); !errors.Is(err, ErrInvalidNumber) {
+				t.Errorf("%d: unexpected error %v", component, err)
+			}
+		}
+		c.Component = 256
+		if _, err := a.Encode(AttributeCandidate, c); err != nil {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package sdp
+
+// Bandwidth is <bwtype> and <bandwidth> sub-fields of Bandwidth field.
+// Unit of Value depends on Type, see BitsPerSecond.
+type Bandwidth struct {
+	Type  BandwidthType
+	Value int
+}
+
+// NewBandwidth returns Bandwidth of type t with value of bps bits per
+// second that is converted to unit of t and rounded up, or false if
+// unit of t is unknown.
+func NewBandwidth(t BandwidthType, bps int) (Bandwidth, bool) {
+	unit := t.bitsPerUnit()
+	if unit == 0 {
+		return Bandwidth{}, false
+	}
+	return Bandwidth{Type: t, Value: (bps + unit - 1) / unit}, true
+}
+
+// BitsPerSecond returns bandwidth in bits per second or false if unit
+// of type is unknown, e.g. for experimental types.
+//
+// Values of CT and AS are kilobits per second, values of TIAS, RS and
+// RR are bits per second.
+func (b Bandwidth) BitsPerSecond() (int, bool) {
+	unit := b.Type.bitsPerUnit()
+	if unit == 0 {
+		return 0, false
+	}
+	return b.Value * unit, true
+}
+
+// Convert returns b converted to type t, e.g. AS kilobits per second to
+// TIAS bits per second, or false if unit of b.Type or t is unknown.
+//
+// Note that TIAS does not include transport overhead (RFC 3890), so
+// conversion between TIAS and AS or CT is only approximation.
+func (b Bandwidth) Convert(t BandwidthType) (Bandwidth, bool) {
+	bps, ok := b.BitsPerSecond()
+	if !ok {
+		return Bandwidth{}, false
+	}
+	return NewBandwidth(t, bps)
+}
+
+// Bandwidths is list of Bandwidth fields in order of appearance.
+type Bandwidths []Bandwidth
+
+// Get returns value of first bandwidth of type t or false if not found.
+func (b Bandwidths) Get(t BandwidthType) (int, bool) {
+	for _, v := range b {
+		if v.Type == t {
+			return v.Value, true
+		}
+	}
+	return 0, false
+}
+
+// Value returns value of first bandwidth of type t or zero.
+func (b Bandwidths) Value(t BandwidthType) int {
+	v, _ := b.Get(t)
+	return v
+}
+
+// BitsPerSecond returns value of first bandwidth of type t in bits per
+// second or false if not found or unit of t is unknown.
+func (b Bandwidths) BitsPerSecond(t BandwidthType) (int, bool) {
+	for _, v := range b {
+		if v.Type == t {
+			return v.BitsPerSecond()
+		}
+	}
+	return 0, false
+}
+
+// Set sets value of first bandwidth of type t or appends new one,
+// returning resulting list.
+func (b Bandwidths) Set(t BandwidthType, v int) Bandwidths {
+	for i := range b {
+		if b[i].Type == t {
+			b[i].Value = v
+			return b
+		}
+	}
+	return append(b, Bandwidth{Type: t, Value: v})
+}
+
+// Delete removes all bandwidths of type t, returning resulting list.
+func (b Bandwidths) Delete(t BandwidthType) Bandwidths {
+	n := 0
+	for _, v := range b {
+		if v.Type != t {
+			b[n] = v
+			n++
+		}
+	}
+	return b[:n]
+}
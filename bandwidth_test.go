@@ -0,0 +1,148 @@
+package sdp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecoder_Bandwidths(t *testing.T) {
+	data := loadData(t, "sdp_session_ex_bandwidths", testCRNL)
+	m, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Bandwidths{
+		{Type: BandwidthApplicationSpecificTransportIndependent, Value: 96000},
+		{Type: BandwidthConferenceTotal, Value: 154798},
+	}
+	if !m.Bandwidths.Equal(expected) {
+		t.Errorf("%v != %v", m.Bandwidths, expected)
+	}
+	expected = Bandwidths{
+		{Type: BandwidthApplicationSpecific, Value: 64},
+		{Type: BandwidthRTCPSenders, Value: 800},
+		{Type: BandwidthRTCPReceivers, Value: 2000},
+		{Type: "X-YZ", Value: 128},
+	}
+	if !m.Medias[0].Bandwidths.Equal(expected) {
+		t.Errorf("%v != %v", m.Medias[0].Bandwidths, expected)
+	}
+	t.Run("Encode", func(t *testing.T) {
+		// Encoding should be byte-stable and keep decoded order.
+		for i := 0; i < 10; i++ {
+			out := m.Append(nil).AppendTo(nil)
+			if !bytes.Equal(bytes.TrimSpace(out), bytes.TrimSpace(data)) {
+				t.Fatalf("%s != %s", out, data)
+			}
+		}
+	})
+	t.Run("InvalidType", func(t *testing.T) {
+		s := Session{
+			{Type: TypeProtocolVersion, Value: []byte("0")},
+			{Type: TypeOrigin, Value: []byte("jdoe 1 1 IN IP4 10.0.0.1")},
+			{Type: TypeSessionName, Value: []byte("-")},
+			{Type: TypeBandwidth, Value: []byte("A(S:64")},
+		}
+		d := NewDecoder(s)
+		if err := d.Decode(new(Message)); err == nil {
+			t.Error("should fail")
+		}
+	})
+}
+
+func TestBandwidthType(t *testing.T) {
+	for _, tc := range []struct {
+		t            BandwidthType
+		registered   bool
+		experimental bool
+	}{
+		{BandwidthConferenceTotal, true, false},
+		{BandwidthApplicationSpecific, true, false},
+		{BandwidthApplicationSpecificTransportIndependent, true, false},
+		{BandwidthRTCPSenders, true, false},
+		{BandwidthRTCPReceivers, true, false},
+		{"X-YZ", false, true},
+		{"FOO", false, false},
+	} {
+		t.Run(string(tc.t), func(t *testing.T) {
+			if tc.t.Registered() != tc.registered {
+				t.Error("unexpected Registered")
+			}
+			if tc.t.Experimental() != tc.experimental {
+				t.Error("unexpected Experimental")
+			}
+		})
+	}
+}
+
+func TestBandwidth_Convert(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   Bandwidth
+		t    BandwidthType
+		out  Bandwidth
+		ok   bool
+	}{
+		{
+			name: "ASToTIAS",
+			in:   Bandwidth{Type: BandwidthApplicationSpecific, Value: 64},
+			t:    BandwidthApplicationSpecificTransportIndependent,
+			out:  Bandwidth{Type: BandwidthApplicationSpecificTransportIndependent, Value: 64000},
+			ok:   true,
+		},
+		{
+			name: "TIASToAS",
+			in:   Bandwidth{Type: BandwidthApplicationSpecificTransportIndependent, Value: 64001},
+			t:    BandwidthApplicationSpecific,
+			out:  Bandwidth{Type: BandwidthApplicationSpecific, Value: 65},
+			ok:   true,
+		},
+		{
+			name: "ASToCT",
+			in:   Bandwidth{Type: BandwidthApplicationSpecific, Value: 64},
+			t:    BandwidthConferenceTotal,
+			out:  Bandwidth{Type: BandwidthConferenceTotal, Value: 64},
+			ok:   true,
+		},
+		{
+			name: "Experimental",
+			in:   Bandwidth{Type: "X-YZ", Value: 64},
+			t:    BandwidthConferenceTotal,
+		},
+		{
+			name: "ToExperimental",
+			in:   Bandwidth{Type: BandwidthApplicationSpecific, Value: 64},
+			t:    "X-YZ",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, ok := tc.in.Convert(tc.t)
+			if ok != tc.ok || out != tc.out {
+				t.Errorf("%v, %v != %v, %v", out, ok, tc.out, tc.ok)
+			}
+		})
+	}
+}
+
+func TestBandwidths(t *testing.T) {
+	var b Bandwidths
+	b = b.Set(BandwidthApplicationSpecific, 64)
+	b = b.Set("X-YZ", 1)
+	b = b.Set(BandwidthApplicationSpecific, 128)
+	if len(b) != 2 || b.Value(BandwidthApplicationSpecific) != 128 {
+		t.Errorf("unexpected %v", b)
+	}
+	if bps, ok := b.BitsPerSecond(BandwidthApplicationSpecific); !ok || bps != 128000 {
+		t.Errorf("unexpected bps %d", bps)
+	}
+	if _, ok := b.BitsPerSecond("X-YZ"); ok {
+		t.Error("unit of experimental type should be unknown")
+	}
+	if _, ok := b.Get(BandwidthConferenceTotal); ok {
+		t.Error("unexpected CT")
+	}
+	b = b.Delete(BandwidthApplicationSpecific)
+	if len(b) != 1 || b[0].Type != "X-YZ" {
+		t.Errorf("unexpected %v", b)
+	}
+}
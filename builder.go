@@ -0,0 +1,337 @@
+package sdp
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNoMedia means that media-level value is added before any media
+// description.
+var ErrNoMedia = errors.New("no media description")
+
+// BuildError lists all problems of message that is built by Builder.
+type BuildError struct {
+	Errors []error // like *ValidationError with ErrMissingField
+}
+
+func (e *BuildError) Error() string {
+	s := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		s[i] = err.Error()
+	}
+	return "failed to build message: " + strings.Join(s, "; ")
+}
+
+// Is reports whether any of e.Errors matches target, so errors.Is can
+// be used to check for ErrMissingField.
+func (e *BuildError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds first of e.Errors that matches target, so errors.As can be
+// used to get *ValidationError.
+func (e *BuildError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Builder builds Message with chained calls, like
+//
+//	m, err := sdp.NewBuilder().
+//		Origin("jdoe", "10.47.16.5").Connection("10.47.16.5", 0, 0).
+//		Audio(49170, "RTP/AVP").Codec(0, "PCMU/8000").SendOnly().
+//		Build()
+//
+// Calls that can be used both at session and media level, like
+// Connection or Attribute, apply to media that was added last, or to
+// session if there are no medias yet.
+type Builder struct {
+	m    Message
+	errs []error
+}
+
+// NewBuilder returns new Builder.
+func NewBuilder() *Builder {
+	return new(Builder)
+}
+
+// media returns last media or nil.
+func (b *Builder) media() *Media {
+	if len(b.m.Medias) == 0 {
+		return nil
+	}
+	return &b.m.Medias[len(b.m.Medias)-1]
+}
+
+// Origin sets username and unicast address of originator. Blank
+// username is replaced by "-".
+func (b *Builder) Origin(username, address string) *Builder {
+	b.m.Origin.Username = username
+	b.m.Origin.Address = address
+	return b
+}
+
+// SessionID sets <sess-id> and <sess-version> of origin, so they are
+// not generated.
+func (b *Builder) SessionID(id, version int64) *Builder {
+	b.m.Origin.SessionID = id
+	b.m.Origin.SessionVersion = version
+	return b
+}
+
+// Name sets session name, which is "-" by default.
+func (b *Builder) Name(name string) *Builder {
+	b.m.Name = name
+	return b
+}
+
+// Info sets session information or media title.
+func (b *Builder) Info(info string) *Builder {
+	if m := b.media(); m != nil {
+		m.Title = info
+	} else {
+		b.m.Info = info
+	}
+	return b
+}
+
+// URI sets session URI.
+func (b *Builder) URI(uri string) *Builder {
+	b.m.URI = uri
+	return b
+}
+
+// Email adds email address of session.
+func (b *Builder) Email(address, name string) *Builder {
+	b.m.Emails = append(b.m.Emails, Address{Address: address, Name: name})
+	return b
+}
+
+// Phone adds phone number of session.
+func (b *Builder) Phone(phone, name string) *Builder {
+	b.m.Phones = append(b.m.Phones, Address{Address: phone, Name: name})
+	return b
+}
+
+// Connection sets connection address, which is IP address or FQDN,
+// with <ttl> and <number of addresses> for multicast. TTL is required
+// for IP4 multicast address and zero values are omitted otherwise.
+func (b *Builder) Connection(address string, ttl, addresses int) *Builder {
+	c := &b.m.Connection
+	if m := b.media(); m != nil {
+		c = m.addConnection()
+	}
+	*c = ConnectionData{TTL: ttl, Addresses: addresses}
+	if ip := net.ParseIP(address); ip != nil {
+		c.IP = ip
+	} else {
+		c.Host = address
+	}
+	return b
+}
+
+// Bandwidth sets bandwidth of type t.
+func (b *Builder) Bandwidth(t BandwidthType, v int) *Builder {
+	if m := b.media(); m != nil {
+		m.Bandwidths = m.Bandwidths.Set(t, v)
+	} else {
+		b.m.Bandwidths = b.m.Bandwidths.Set(t, v)
+	}
+	return b
+}
+
+// Timing adds time description. Default one is "t=0 0", which is
+// permanent session.
+func (b *Builder) Timing(start, end time.Time) *Builder {
+	b.m.Timing = append(b.m.Timing, Timing{Start: start, End: end})
+	return b
+}
+
+// Encryption sets encryption key.
+func (b *Builder) Encryption(e Encryption) *Builder {
+	if m := b.media(); m != nil {
+		m.Encryption = e
+	} else {
+		b.m.Encryption = e
+	}
+	return b
+}
+
+// Attribute adds attribute in "a=<key>:<value>" form or in "a=<flag>"
+// form if there are no values.
+func (b *Builder) Attribute(key string, values ...string) *Builder {
+	v := strings.Join(values, " ")
+	if m := b.media(); m != nil {
+		m.Attributes = addAttribute(m.Attributes, key, v)
+	} else {
+		b.m.Attributes = addAttribute(b.m.Attributes, key, v)
+	}
+	return b
+}
+
+// Media adds media description that following calls apply to.
+func (b *Builder) Media(mediaType string, port int, protocol string) *Builder {
+	b.m.Medias = append(b.m.Medias, Media{
+		Description: MediaDescription{
+			Type:     mediaType,
+			Port:     port,
+			Protocol: protocol,
+		},
+	})
+	return b
+}
+
+// Audio adds audio media description.
+func (b *Builder) Audio(port int, protocol string) *Builder {
+	return b.Media("audio", port, protocol)
+}
+
+// Video adds video media description.
+func (b *Builder) Video(port int, protocol string) *Builder {
+	return b.Media("video", port, protocol)
+}
+
+// Codec adds RTP payload type to formats of last media with
+// "a=rtpmap:<payload type> <encoding>" attribute, where encoding is
+// like "PCMU/8000" or "opus/48000/2".
+func (b *Builder) Codec(payloadType int, encoding string) *Builder {
+	m := b.media()
+	if m == nil {
+		b.errs = append(b.errs, errors.Wrapf(ErrNoMedia, "codec %d", payloadType))
+		return b
+	}
+	pt := strconv.Itoa(payloadType)
+	var rtpMap RTPMap
+	if err := (RTPMapCodec{}).Decode(pt+" "+encoding, &rtpMap); err != nil {
+		b.errs = append(b.errs, errors.Wrapf(err, "codec %d", payloadType))
+		return b
+	}
+	m.Description.Formats = append(m.Description.Formats, pt)
+	m.Attributes = addAttribute(m.Attributes, AttributeRTPMap, pt+" "+encoding)
+	return b
+}
+
+// Format adds format that has no rtpmap attribute to last media, like
+// "0" for static RTP payload type or "webrtc-datachannel".
+func (b *Builder) Format(format string) *Builder {
+	m := b.media()
+	if m == nil {
+		b.errs = append(b.errs, errors.Wrapf(ErrNoMedia, "format %s", format))
+		return b
+	}
+	m.Description.Formats = append(m.Description.Formats, format)
+	return b
+}
+
+func (b *Builder) direction(d Direction) *Builder {
+	a := &b.m.Attributes
+	if m := b.media(); m != nil {
+		a = &m.Attributes
+	}
+	filtered := (*a)[:0]
+	for _, v := range *a {
+		if !isDirection(v.Key) {
+			filtered = append(filtered, v)
+		}
+	}
+	*a = addAttribute(filtered, string(d), blank)
+	return b
+}
+
+// SendRecv sets "a=sendrecv" direction.
+func (b *Builder) SendRecv() *Builder { return b.direction(DirectionSendRecv) }
+
+// SendOnly sets "a=sendonly" direction.
+func (b *Builder) SendOnly() *Builder { return b.direction(DirectionSendOnly) }
+
+// RecvOnly sets "a=recvonly" direction.
+func (b *Builder) RecvOnly() *Builder { return b.direction(DirectionRecvOnly) }
+
+// Inactive sets "a=inactive" direction.
+func (b *Builder) Inactive() *Builder { return b.direction(DirectionInactive) }
+
+// newSessionID returns random positive 62-bit <sess-id>, falling back
+// to NTP timestamp as suggested by RFC 8866 Section 5.2.
+func newSessionID() int64 {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return int64(TimeToNTP(time.Now()))
+	}
+	return int64(binary.BigEndian.Uint64(buf[:])>>2) + 1
+}
+
+// appendMulticastTTLError appends error to errs if c is IP4 multicast
+// address without TTL, which is required by RFC 8866 Section 5.7.
+func appendMulticastTTLError(errs []error, field string, c ConnectionData) []error {
+	if c.TTL == 0 && isIPv4(c.IP) && c.IP.IsMulticast() {
+		errs = append(errs, &ValidationError{Field: field + ".TTL", Err: ErrMissingField})
+	}
+	return errs
+}
+
+// Build fills RFC defaults, like "v=0", "t=0 0" and generated
+// <sess-id>, and returns copy of built Message. Error is *BuildError
+// that lists missing fields, like "c=" that is neither at session level
+// nor in every media, and other problems.
+func (b *Builder) Build() (*Message, error) {
+	if b.m.Origin.SessionID == 0 {
+		b.m.Origin.SessionID = newSessionID()
+	}
+	m := b.m.Clone()
+	m.Version = 0
+	if m.Origin.Username == "" {
+		m.Origin.Username = "-"
+	}
+	if m.Name == "" {
+		m.Name = "-"
+	}
+	if len(m.Timing) == 0 {
+		m.Timing = []Timing{{}}
+	}
+	errs := append([]error(nil), b.errs...)
+	if m.Origin.Address == "" {
+		errs = append(errs, &ValidationError{Field: "Origin.Address", Err: ErrMissingField})
+	}
+	errs = appendMulticastTTLError(errs, "Connection", m.Connection)
+	for i := range m.Medias {
+		prefix := "Medias[" + strconv.Itoa(i) + "]."
+		if len(m.Medias[i].Description.Formats) == 0 {
+			field := prefix + "Description.Formats"
+			errs = append(errs, &ValidationError{Field: field, Err: ErrMissingField})
+		}
+		// RFC 8866 Section 5.7: "c=" field MUST be at session level
+		// or in each media description.
+		if m.Connection.Blank() && len(m.Medias[i].Connections) == 0 {
+			field := prefix + "Connections"
+			errs = append(errs, &ValidationError{Field: field, Err: ErrMissingField})
+		}
+		for j, c := range m.Medias[i].Connections {
+			field := prefix + "Connections[" + strconv.Itoa(j) + "]"
+			errs = appendMulticastTTLError(errs, field, c)
+		}
+	}
+	if len(errs) == 0 {
+		if err := m.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &BuildError{Errors: errs}
+	}
+	return m, nil
+}
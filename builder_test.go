@@ -0,0 +1,161 @@
+package sdp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestBuilder(t *testing.T) {
+	m, err := NewBuilder().
+		Origin("jdoe", "10.47.16.5").SessionID(2890844526, 2890842807).
+		Name("SDP Seminar").
+		Connection("224.2.17.12", 127, 0).
+		Bandwidth(BandwidthConferenceTotal, 154798).
+		RecvOnly().
+		Audio(49170, "RTP/AVP").Codec(0, "PCMU/8000").SendOnly().
+		Video(51372, "RTP/AVP").Codec(99, "h263-1998/90000").Info("Camera").
+		Bandwidth(BandwidthApplicationSpecific, 66781).
+		Attribute("ptime", "20").SendOnly().Inactive().
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := m.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Join([]string{
+		"v=0",
+		"o=jdoe 2890844526 2890842807 IN IP4 10.47.16.5",
+		"s=SDP Seminar",
+		"c=IN IP4 224.2.17.12/127",
+		"b=CT:154798",
+		"t=0 0",
+		"a=recvonly",
+		"m=audio 49170 RTP/AVP 0",
+		"a=rtpmap:0 PCMU/8000",
+		"a=sendonly",
+		"m=video 51372 RTP/AVP 99",
+		"i=Camera",
+		"b=AS:66781",
+		"a=rtpmap:99 h263-1998/90000",
+		"a=ptime:20",
+		"a=inactive",
+	}, "\r\n") + "\r\n"
+	if string(b) != expected {
+		t.Errorf("%s\n!=\n%s", b, expected)
+	}
+}
+
+func TestBuilder_Defaults(t *testing.T) {
+	b := NewBuilder().Origin("", "example.com").Connection("example.com", 0, 0).Media("application", 9, "UDP/DTLS/SCTP").Format("webrtc-datachannel")
+	m, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Version != 0 || m.Name != "-" || m.Origin.Username != "-" {
+		t.Error("unexpected defaults", m.Version, m.Name, m.Origin.Username)
+	}
+	if len(m.Timing) != 1 || !m.Timing[0].Equal(Timing{}) {
+		t.Error("unexpected timing", m.Timing)
+	}
+	if m.Origin.SessionID <= 0 {
+		t.Error("sess-id should be generated", m.Origin.SessionID)
+	}
+	again, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Origin.SessionID != m.Origin.SessionID {
+		t.Error("sess-id should be same for builder")
+	}
+	if again == m {
+		t.Error("should return copy")
+	}
+}
+
+func TestBuilder_Errors(t *testing.T) {
+	_, err := NewBuilder().
+		Codec(0, "PCMU/8000").
+		Audio(49170, "RTP/AVP").Codec(96, "opus").
+		Video(51372, "RTP/AVP").
+		Build()
+	var buildErr *BuildError
+	if !errors.As(err, &buildErr) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(buildErr.Errors) != 7 {
+		t.Fatal("unexpected errors", buildErr)
+	}
+	for i, target := range []error{
+		ErrNoMedia, ErrInvalidSyntax, ErrMissingField, ErrMissingField,
+		ErrMissingField, ErrMissingField, ErrMissingField,
+	} {
+		if !errors.Is(buildErr.Errors[i], target) {
+			t.Errorf("[%d] %v is not %v", i, buildErr.Errors[i], target)
+		}
+	}
+	var validationErr *ValidationError
+	if !errors.As(buildErr.Errors[3], &validationErr) || validationErr.Field != "Medias[0].Description.Formats" {
+		t.Error("unexpected error", buildErr.Errors[3])
+	}
+	for _, tc := range []struct {
+		name  string
+		b     *Builder
+		field string
+	}{
+		{
+			name:  "NoConnection",
+			b:     NewBuilder().Origin("jdoe", "2001:db8::1").Audio(49170, "RTP/AVP").Codec(0, "PCMU/8000"),
+			field: "Medias[0].Connections",
+		},
+		{
+			name: "NoMediaConnection",
+			b: NewBuilder().Origin("jdoe", "2001:db8::1").
+				Audio(49170, "RTP/AVP").Codec(0, "PCMU/8000").Connection("2001:db8::1", 0, 0).
+				Video(51372, "RTP/AVP").Codec(99, "h263-1998/90000"),
+			field: "Medias[1].Connections",
+		},
+		{
+			name:  "MulticastTTL",
+			b:     NewBuilder().Origin("jdoe", "10.47.16.5").Connection("224.2.17.12", 0, 0),
+			field: "Connection.TTL",
+		},
+		{
+			name: "MediaMulticastTTL",
+			b: NewBuilder().Origin("jdoe", "10.47.16.5").
+				Audio(49170, "RTP/AVP").Codec(0, "PCMU/8000").Connection("224.2.1.1", 0, 3),
+			field: "Medias[0].Connections[0].TTL",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.b.Build()
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tc.field {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !errors.Is(err, ErrMissingField) {
+				t.Error("should be ErrMissingField")
+			}
+		})
+	}
+	t.Run("Multicast", func(t *testing.T) {
+		m, err := NewBuilder().Origin("jdoe", "10.47.16.5").
+			Audio(49170, "RTP/AVP").Codec(0, "PCMU/8000").Connection("224.2.1.1", 127, 3).
+			Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v := m.Medias[0].Connections[0].ConnectionAddress(); v != "224.2.1.1/127/3" {
+			t.Errorf("unexpected connection %q", v)
+		}
+	})
+	t.Run("Validate", func(t *testing.T) {
+		_, err := NewBuilder().Origin("jdoe", "10.47.16.5").Attribute("tool", "a\nb").Build()
+		if !errors.Is(err, ErrInvalidByteString) {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package sdp
+
+import (
+	"net"
+	"time"
+)
+
+// cloneString returns copy of s that does not share memory with it,
+// because strings of decoded message can reference mutable memory.
+func cloneString(s string) string {
+	if len(s) == 0 {
+		return blank
+	}
+	return string([]byte(s))
+}
+
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	c := make([]string, len(s))
+	for i := range s {
+		c[i] = cloneString(s[i])
+	}
+	return c
+}
+
+func cloneAddresses(a []Address) []Address {
+	if a == nil {
+		return nil
+	}
+	c := make([]Address, len(a))
+	for i := range a {
+		c[i] = Address{
+			Address:       cloneString(a[i].Address),
+			Name:          cloneString(a[i].Name),
+			AngleBrackets: a[i].AngleBrackets,
+		}
+	}
+	return c
+}
+
+func cloneIP(ip net.IP) net.IP {
+	if ip == nil {
+		return nil
+	}
+	c := make(net.IP, len(ip))
+	copy(c, ip)
+	return c
+}
+
+func (a Attributes) clone() Attributes {
+	if a == nil {
+		return nil
+	}
+	c := make(Attributes, len(a))
+	for i := range a {
+		c[i] = Attribute{
+			Key:   cloneString(a[i].Key),
+			Value: cloneString(a[i].Value),
+		}
+	}
+	return c
+}
+
+func (b Bandwidths) clone() Bandwidths {
+	if b == nil {
+		return nil
+	}
+	c := make(Bandwidths, len(b))
+	for i := range b {
+		c[i] = Bandwidth{
+			Type:  BandwidthType(cloneString(string(b[i].Type))),
+			Value: b[i].Value,
+		}
+	}
+	return c
+}
+
+func (c ConnectionData) clone() ConnectionData {
+	c.NetworkType = cloneString(c.NetworkType)
+	c.AddressType = cloneString(c.AddressType)
+	c.IP = cloneIP(c.IP)
+	c.Host = cloneString(c.Host)
+	switch a := c.Address.(type) {
+	case RawAddress:
+		c.Address = RawAddress(cloneString(string(a)))
+	case PSTNAddress:
+		c.Address = PSTNAddress(cloneString(string(a)))
+	case ATMAddress:
+		c.Address = ATMAddress(cloneString(string(a)))
+	case NetworkAddressCloner:
+		c.Address = a.CloneAddress()
+	}
+	return c
+}
+
+func cloneConnections(c []ConnectionData) []ConnectionData {
+	if c == nil {
+		return nil
+	}
+	cloned := make([]ConnectionData, len(c))
+	for i := range c {
+		cloned[i] = c[i].clone()
+	}
+	return cloned
+}
+
+func (e Encryption) clone() Encryption {
+	return Encryption{
+		Method: cloneString(e.Method),
+		Key:    cloneString(e.Key),
+	}
+}
+
+func (r RawSection) clone() RawSection {
+	return RawSection{
+		Lines:     r.Lines.clone(),
+		canonical: r.canonical.clone(),
+	}
+}
+
+// Clone returns deep copy of m that does not share any memory with it.
+func (m *Message) Clone() *Message {
+	c := &Message{
+		Version: m.Version,
+		Origin: Origin{
+			Username:       cloneString(m.Origin.Username),
+			SessionID:      m.Origin.SessionID,
+			SessionVersion: m.Origin.SessionVersion,
+			NetworkType:    cloneString(m.Origin.NetworkType),
+			AddressType:    cloneString(m.Origin.AddressType),
+			Address:        cloneString(m.Origin.Address),
+		},
+		Name:       cloneString(m.Name),
+		Info:       cloneString(m.Info),
+		Emails:     cloneAddresses(m.Emails),
+		Phones:     cloneAddresses(m.Phones),
+		URI:        cloneString(m.URI),
+		Connection: m.Connection.clone(),
+		Attributes: m.Attributes.clone(),
+		Encryption: m.Encryption.clone(),
+		Bandwidths: m.Bandwidths.clone(),
+		Raw:        m.Raw.clone(),
+	}
+	if m.Medias != nil {
+		c.Medias = make(Medias, len(m.Medias))
+		for i := range m.Medias {
+			c.Medias[i] = m.Medias[i].Clone()
+		}
+	}
+	if m.Timing != nil {
+		c.Timing = make([]Timing, len(m.Timing))
+		for i, t := range m.Timing {
+			if t.Offsets != nil {
+				t.Offsets = append([]time.Duration(nil), t.Offsets...)
+			}
+			c.Timing[i] = t
+		}
+	}
+	if m.TZAdjustments != nil {
+		c.TZAdjustments = append([]TimeZone(nil), m.TZAdjustments...)
+	}
+	return c
+}
+
+// Clone returns deep copy of m that does not share any memory with it.
+func (m *Media) Clone() Media {
+	return Media{
+		Title: cloneString(m.Title),
+		Description: MediaDescription{
+			Type:        cloneString(m.Description.Type),
+			Port:        m.Description.Port,
+			PortsNumber: m.Description.PortsNumber,
+			Protocol:    cloneString(m.Description.Protocol),
+			Formats:     cloneStrings(m.Description.Formats),
+		},
+		Connections: cloneConnections(m.Connections),
+		Attributes:  m.Attributes.clone(),
+		Encryption:  m.Encryption.clone(),
+		Bandwidths:  m.Bandwidths.clone(),
+		Raw:         m.Raw.clone(),
+	}
+}
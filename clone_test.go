@@ -0,0 +1,65 @@
+package sdp
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMessage_Clone(t *testing.T) {
+	s, err := DecodeSession(loadData(t, "sdp_session_ex_full", testCRNL), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := new(Message)
+	d := NewDecoderWithOptions(s, DecoderOptions{Lossless: true})
+	if err = d.Decode(m); err != nil {
+		t.Fatal(err)
+	}
+	m.Timing[0].Offsets = []time.Duration{0, 25 * time.Hour}
+	m.TZAdjustments = []TimeZone{{Start: time.Unix(100, 0), Offset: time.Hour}}
+	c := m.Clone()
+	expected := *m
+	if !reflect.DeepEqual(*c, expected) {
+		t.Fatalf("%+v != %+v", c, expected)
+	}
+	// Decoded strings of expected reference memory of m.
+	expected = *c
+	t.Run("NoAliasing", func(t *testing.T) {
+		c := m.Clone()
+		c.Attributes[0].Key = "changed"
+		c.Medias[1].Attributes[0].Value = "changed"
+		c.Medias[1].Description.Formats[0] = "changed"
+		c.Medias[1].Bandwidths[0].Value = 1
+		c.Bandwidths[0].Type = "X-YZ"
+		c.Connection.IP[0] = 1
+		c.Timing[0].Offsets[0] = time.Second
+		c.TZAdjustments[0].Offset = 0
+		c.Raw.Lines[0].Value[0] = '1'
+		if !reflect.DeepEqual(*m.Clone(), expected) {
+			t.Error("original message changed")
+		}
+	})
+	t.Run("Reset", func(t *testing.T) {
+		c := m.Clone()
+		data := loadData(t, "sdp_session_ex_mediac", testNL)
+		s, err = DecodeSession(data, s[:0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Reset()
+		d.Reset(s)
+		if err = d.Decode(m); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(*c, expected) {
+			t.Error("clone changed after reuse of original message")
+		}
+	})
+	t.Run("Blank", func(t *testing.T) {
+		c := new(Message).Clone()
+		if !reflect.DeepEqual(*c, Message{}) {
+			t.Errorf("unexpected clone %+v", c)
+		}
+	})
+}
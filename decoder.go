@@ -7,6 +7,7 @@ import (
 	"net"
 	"strconv"
 	"time"
+	"unicode/utf8"
 	"unsafe"
 
 	"github.com/pkg/errors"
@@ -14,7 +15,93 @@ import (
 
 const blank = ""
 
+// DecoderOptions configures Decoder. Zero value is valid default.
+type DecoderOptions struct {
+	// Lossless enables capturing of original lines, including lines
+	// of unknown types, to Raw fields of Message and Media, so
+	// Message.Append can reproduce them verbatim if message was
+	// not modified.
+	Lossless bool
+	// ZeroCopy disables copying of decoded strings, so they reference
+	// memory of Session lines and decoding to reused Message can be
+	// done without allocations. Session must not be modified or reused
+	// until decoded Message is in use.
+	ZeroCopy bool
+	// AllErrors enables decoding of all lines even after errors, so
+	// all problems are reported at once as ErrorList. Lines that
+	// failed to decode are skipped.
+	AllErrors bool
+	// Strict enables all checks of LenientRules, except ones that are
+	// explicitly relaxed in Lenient. By default, DefaultLenient rules
+	// are relaxed.
+	Strict bool
+	// Lenient is set of relaxed rules. Violations of relaxed rules are
+	// not errors and are reported by Decoder.Warnings instead.
+	Lenient LenientRules
+	// Spec is specification to follow, RFC4566 by default. Obsolete
+	// fields of RFC8866 are reported by Decoder.Warnings.
+	Spec Spec
+	// Limits bound resources that are used for decoding, so Decode
+	// fails with ErrLimitExceeded on oversized input even if AllErrors
+	// is set. DefaultLimits are used for zero fields.
+	Limits Limits
+}
+
+// LenientRules is set of decoding rules that can be relaxed to accept
+// SDP from non-compliant implementations.
+type LenientRules uint
+
+// Rules that can be relaxed.
+const (
+	// LenientConnectionAfterBandwidth allows c= after b= in section.
+	LenientConnectionAfterBandwidth LenientRules = 1 << iota
+	// LenientEncryptionBeforeConnection allows k= before c= in section.
+	LenientEncryptionBeforeConnection
+	// LenientMissingTiming allows message without time description.
+	LenientMissingTiming
+	// LenientExtraSpaces allows multiple spaces between subfields,
+	// leading and trailing spaces.
+	LenientExtraSpaces
+	// LenientTimeZonesBeforeTiming allows z= before time descriptions
+	// in RFC 4566 messages, as it was decoded by earlier versions.
+	LenientTimeZonesBeforeTiming
+
+	// DefaultLenient is set of rules that are relaxed if
+	// DecoderOptions.Strict is not set.
+	DefaultLenient = LenientConnectionAfterBandwidth |
+		LenientEncryptionBeforeConnection |
+		LenientMissingTiming |
+		LenientTimeZonesBeforeTiming
+	// LenientAll relaxes all rules.
+	LenientAll = DefaultLenient | LenientExtraSpaces
+)
+
+func (o DecoderOptions) lenient() LenientRules {
+	if o.Strict {
+		return o.Lenient
+	}
+	return o.Lenient | DefaultLenient
+}
+
+// fieldSet is set of field types, where each type is represented
+// by bit of its letter.
+type fieldSet uint32
+
+func (s fieldSet) has(t Type) bool {
+	return s&(1<<(t-'a')) != 0
+}
+
+func (s *fieldSet) add(t Type) {
+	if t >= 'a' && t <= 'z' {
+		*s |= 1 << (t - 'a')
+	}
+}
+
 // Decoder decodes session.
+//
+// By default, decoded Message does not reference memory of Session,
+// so Session can be safely reused or modified after decoding.
+// See DecoderOptions.ZeroCopy.
 type Decoder struct {
 	s       Session
 	pos     int
@@ -24,12 +111,44 @@ type Decoder struct {
 	section section
 	sPos    int
 	m       Media
+	opts    DecoderOptions
+	col     int      // offset of error in d.v
+	timings int      // count of time descriptions
+	media   int      // index of current media description
+	seen    fieldSet // fields of current section
+	limits  Limits
+	msg     *Message // message that is decoded
+	fields  [][]byte // buffer for subfields
+	medias  int      // count of decoded media descriptions
+	attrs   int      // count of attributes in current section
+	errs    ErrorList
+	warns   ErrorList
 }
 
 // NewDecoder returns Decoder for Session.
 func NewDecoder(s Session) Decoder {
+	return NewDecoderWithOptions(s, DecoderOptions{})
+}
+
+// NewDecoderWithOptions returns Decoder for Session that is configured
+// with opts.
+func NewDecoderWithOptions(s Session, opts DecoderOptions) Decoder {
 	return Decoder{
-		s: s,
+		s:      s,
+		opts:   opts,
+		limits: opts.Limits.withDefaults(),
+	}
+}
+
+// Reset resets decoder to decode s, keeping options and internal
+// buffers, so decoding can be done without allocations. See
+// Message.Reset.
+func (d *Decoder) Reset(s Session) {
+	*d = Decoder{
+		s:      s,
+		opts:   d.opts,
+		limits: d.limits,
+		fields: d.fields[:0],
 	}
 }
 
@@ -47,10 +166,141 @@ func (d *Decoder) next() bool {
 	d.l = d.s[d.pos]
 	d.v = d.l.Value
 	d.t = d.l.Type
+	d.col = 0
 	d.pos++
 	return true
 }
 
+// offset returns offset of b in d.v, where b is sub-slice of d.v.
+func (d *Decoder) offset(b []byte) int {
+	return cap(d.v) - cap(b)
+}
+
+// fieldError returns FieldError for current line with err as cause.
+func (d *Decoder) fieldError(code ErrorCode, err error) *FieldError {
+	e := &FieldError{
+		Line:   d.pos,
+		Column: utf8.RuneLen(rune(d.t)) + 2 + d.col,
+		Type:   d.t,
+		Code:   code,
+		Err:    err,
+	}
+	switch d.section {
+	case sectionTime:
+		e.Section = SectionTime
+		e.Index = d.timings - 1
+	case sectionMedia:
+		e.Section = SectionMedia
+		e.Index = d.media
+	default:
+		e.Section = SectionSession
+	}
+	return e
+}
+
+// fail returns FieldError for current line with err as cause, or
+// nil if error is collected to be returned later.
+func (d *Decoder) fail(code ErrorCode, err error) error {
+	return d.collect(d.fieldError(code, err))
+}
+
+func missingError(t Type, err error) *FieldError {
+	return &FieldError{
+		Type:    t,
+		Section: SectionSession,
+		Code:    CodeMissingField,
+		Err:     err,
+	}
+}
+
+// failMissing returns FieldError for required field t that is missing
+// in message, or nil if error is collected to be returned later.
+func (d *Decoder) failMissing(t Type, err error) error {
+	return d.collect(missingError(t, err))
+}
+
+// relax records e as warning and returns nil if rule is relaxed,
+// failing with e otherwise.
+func (d *Decoder) relax(rule LenientRules, e *FieldError) error {
+	if !d.relaxed(rule) {
+		return d.collect(e)
+	}
+	d.warns = append(d.warns, e)
+	return nil
+}
+
+func (d *Decoder) relaxed(rule LenientRules) bool {
+	return d.opts.lenient()&rule != 0
+}
+
+// checkLimit returns *LimitError if n exceeds max.
+func checkLimit(name string, n, max int) error {
+	if exceeds(n, max) {
+		return &LimitError{Limit: name, Max: max}
+	}
+	return nil
+}
+
+// checkLines checks count and length of session lines.
+func (d *Decoder) checkLines() error {
+	if exceeds(len(d.s), d.limits.Lines) {
+		d.pos = d.limits.Lines + 1
+		d.t = d.s[d.limits.Lines].Type
+		return d.fieldError(CodeLimitExceeded, checkLimit("Lines", len(d.s), d.limits.Lines))
+	}
+	for i, l := range d.s {
+		// Type and "=" are included.
+		if err := checkLimit("LineLength", len(l.Value)+2, d.limits.LineLength); err != nil {
+			d.pos = i + 1
+			d.t = l.Type
+			return d.fieldError(CodeLimitExceeded, err)
+		}
+	}
+	return nil
+}
+
+// checkOrder checks ordering rules of current section that can be
+// relaxed, returning true if current line should be skipped.
+func (d *Decoder) checkOrder() (skip bool, err error) {
+	var (
+		rule LenientRules
+		msg  string
+	)
+	if d.t == TypeConnectionData {
+		switch {
+		case d.seen.has(TypeBandwidth):
+			rule, msg = LenientConnectionAfterBandwidth, "connection data after bandwidth"
+		case d.seen.has(TypeEncryptionKey):
+			rule, msg = LenientEncryptionBeforeConnection, "encryption key before connection data"
+		}
+	}
+	if d.t == TypeTimeZones && d.section == sectionSession {
+		rule, msg = LenientTimeZonesBeforeTiming, "time zones before time description"
+	}
+	d.seen.add(d.t)
+	if rule == 0 {
+		return false, nil
+	}
+	err = newSectionDecodeError(d.section, msg)
+	if err = d.relax(rule, d.fieldError(CodeUnexpectedField, err)); err != nil {
+		return false, err
+	}
+	return !d.relaxed(rule), nil
+}
+
+// Warnings returns violations of relaxed rules from last Decode call.
+func (d *Decoder) Warnings() ErrorList {
+	return d.warns
+}
+
+func (d *Decoder) collect(e *FieldError) error {
+	if d.opts.AllErrors && e.Code != CodeLimitExceeded {
+		d.errs = append(d.errs, e)
+		return nil
+	}
+	return e
+}
+
 type section int
 
 const (
@@ -80,22 +330,44 @@ var orderingSession = ordering{
 	TypeSessionName,
 	TypeSessionInformation,
 	TypeURI,
-	TypeEmail,
-	TypePhone,
+	TypeEmail, // 0 or more
+	TypePhone, // 0 or more
 	TypeConnectionData,
 	TypeBandwidth,     // 0 or more
-	TypeTimeZones,     // *
+	TypeTimeZones,     // * checked by Decoder.checkOrder
 	TypeEncryptionKey, // ordering after time start
 	TypeAttribute,     // 0 or more
 }
 
 const orderingAfterTime = 10
 
+// orderingTime is ordering of time description. Time zones follow
+// time descriptions in RFC 4566 and are part of repeat description in
+// RFC 8866, so both are checked by Decoder.decodeTiming.
 var orderingTime = ordering{
 	TypeTiming,
 	TypeRepeatTimes,
+	TypeTimeZones,
 }
 
+// orderingSession8866 is orderingSession of RFC 8866, where time zones
+// are part of time description.
+var orderingSession8866 = ordering{
+	TypeProtocolVersion,
+	TypeOrigin,
+	TypeSessionName,
+	TypeSessionInformation,
+	TypeURI,
+	TypeEmail, // 0 or more
+	TypePhone, // 0 or more
+	TypeConnectionData,
+	TypeBandwidth,     // 0 or more
+	TypeEncryptionKey, // ordering after time start
+	TypeAttribute,     // 0 or more
+}
+
+const orderingAfterTime8866 = 9
+
 var orderingMedia = ordering{
 	TypeMediaDescription,
 	TypeSessionInformation, // title
@@ -124,10 +396,10 @@ func isKnown(t Type) bool {
 	}
 }
 
-// isExpected determines if t is expected on pos in s section and returns nil,
-// if it is expected and DecodeError if not.
-func isExpected(t Type, s section, pos int) error {
-	o := getOrdering(s)
+// isExpected determines if t is expected on pos in s section of spec
+// and returns nil, if it is expected and DecodeError if not.
+func isExpected(spec Spec, t Type, s section, pos int) error {
+	o := getOrdering(spec, s)
 	if len(o) > pos {
 		for _, expected := range o[pos:] {
 			if expected == t {
@@ -142,18 +414,19 @@ func isExpected(t Type, s section, pos int) error {
 	// Checking possible section transitions.
 	switch s {
 	case sectionSession:
-		if pos < orderingAfterTime && isExpected(t, sectionTime, 0) == nil {
+		// Time description starts with timing.
+		if pos < afterTime(spec) && t == TypeTiming {
 			return nil
 		}
-		if isExpected(t, sectionMedia, 0) == nil {
+		if isExpected(spec, t, sectionMedia, 0) == nil {
 			return nil
 		}
 	case sectionTime:
-		if isExpected(t, sectionSession, orderingAfterTime) == nil {
+		if isExpected(spec, t, sectionSession, afterTime(spec)) == nil {
 			return nil
 		}
 	case sectionMedia:
-		if pos != 0 && isExpected(t, sectionMedia, 0) == nil {
+		if pos != 0 && isExpected(spec, t, sectionMedia, 0) == nil {
 			return nil
 		}
 	}
@@ -168,9 +441,12 @@ func isExpected(t Type, s section, pos int) error {
 	return errors.Wrapf(err, "field %s is unexpected", t)
 }
 
-func getOrdering(s section) ordering {
+func getOrdering(spec Spec, s section) ordering {
 	switch s {
 	case sectionSession:
+		if spec == RFC8866 {
+			return orderingSession8866
+		}
 		return orderingSession
 	case sectionMedia:
 		return orderingMedia
@@ -181,6 +457,15 @@ func getOrdering(s section) ordering {
 	}
 }
 
+// afterTime returns position in session ordering of spec that
+// follows time descriptions.
+func afterTime(spec Spec) int {
+	if spec == RFC8866 {
+		return orderingAfterTime8866
+	}
+	return orderingAfterTime
+}
+
 func isOptional(t Type) bool {
 	switch t {
 	case TypeProtocolVersion, TypeOrigin, TypeSessionName:
@@ -196,7 +481,9 @@ func isOptional(t Type) bool {
 
 func isZeroOrMore(t Type) bool {
 	switch t {
-	case TypeBandwidth, TypeAttribute:
+	case TypeEmail, TypePhone, TypeBandwidth, TypeAttribute:
+		return true
+	case TypeRepeatTimes: // *repeat-field in time description
 		return true
 	default:
 		return false
@@ -209,49 +496,68 @@ func newSectionDecodeError(s section, m string) DecodeError {
 }
 
 func (d *Decoder) decodeKV() (k, v string, err error) {
-	var (
-		key     []byte
-		value   []byte
-		isValue bool
-	)
-	for _, v := range d.v {
-		if v == ':' && !isValue {
-			isValue = true
-			continue
-		}
-		if isValue {
-			value = append(value, v)
-		} else {
-			key = append(key, v)
-		}
+	delimiter := bytes.IndexByte(d.v, attributesDelimiter)
+	if delimiter < 0 {
+		d.decodeString(d.v, &k)
+		return k, blank, nil
 	}
-
-	if isValue && len(value) < 1 {
+	if delimiter == len(d.v)-1 {
+		d.col = delimiter + 1
 		msg := fmt.Sprintf("attribute without value")
 		err := newSectionDecodeError(d.section, msg)
 		return "", "", err
 	}
-
-	return string(key), string(value), nil
+	d.decodeString(d.v[:delimiter], &k)
+	d.decodeString(d.v[delimiter+1:], &v)
+	return k, v, nil
 }
 
 func (d *Decoder) decodeTiming(m *Message) error {
 	d.sPos = 0
 	d.section = sectionTime
+	var seen fieldSet
 	for d.next() {
-		if err := isExpected(d.t, d.section, d.sPos); err != nil {
+		if err := isExpected(d.opts.Spec, d.t, d.section, d.sPos); err != nil {
 			if canSkip(err) {
 				continue
 			}
-			return errors.Wrap(err, "decode failed")
+			if err = d.fail(CodeUnexpectedField, err); err != nil {
+				return errors.Wrap(err, "decode failed")
+			}
+			continue
 		}
 		if !isZeroOrMore(d.t) {
 			d.sPos++
 		}
 		switch d.t {
-		case TypeTiming, TypeRepeatTimes:
+		case TypeTiming, TypeRepeatTimes, TypeTimeZones:
+			if d.t == TypeTiming {
+				d.timings++
+			}
+			if d.t == TypeTimeZones && (seen.has(TypeTimeZones) || d.seen.has(TypeTimeZones)) {
+				// Time zones are already decoded in this or before time
+				// description.
+				msg := "multiple time zones fields"
+				err := d.fail(CodeUnexpectedField, newSectionDecodeError(d.section, msg))
+				if err != nil {
+					return errors.Wrap(err, "decode failed")
+				}
+				continue
+			}
+			if d.t == TypeTimeZones && d.opts.Spec == RFC8866 && !seen.has(TypeRepeatTimes) {
+				// RFC 8866: repeat-description = 1*repeat-field [zone-field]
+				msg := "time zones without repeat times"
+				err := d.fail(CodeUnexpectedField, newSectionDecodeError(d.section, msg))
+				if err != nil {
+					return errors.Wrap(err, "decode failed")
+				}
+				continue
+			}
+			seen.add(d.t)
 			if err := d.decodeField(m); err != nil {
-				return errors.Wrap(err, "decode failed")
+				if err = d.fail(errorCode(err), err); err != nil {
+					return errors.Wrap(err, "decode failed")
+				}
 			}
 		default:
 			// possible switch to Media or Session description
@@ -266,22 +572,42 @@ func (d *Decoder) decodeMedia(m *Message) error {
 	d.sPos = 0
 	d.section = sectionMedia
 	d.m = Media{}
+	if cap(m.Medias) > len(m.Medias) {
+		// Reusing memory of media that is left by Message.Reset.
+		d.m = m.Medias[:len(m.Medias)+1][len(m.Medias)]
+		d.m.reset()
+	}
+	d.media = len(m.Medias)
+	d.seen = 0
+	d.attrs = 0
 	for d.next() {
-		if err := isExpected(d.t, d.section, d.sPos); err != nil {
+		if err := isExpected(d.opts.Spec, d.t, d.section, d.sPos); err != nil {
 			if canSkip(err) {
 				continue
 			}
-			return errors.Wrap(err, "decode failed")
+			if err = d.fail(CodeUnexpectedField, err); err != nil {
+				return errors.Wrap(err, "decode failed")
+			}
+			continue
 		}
 		if d.t == TypeMediaDescription && d.sPos != 0 {
 			d.pos--
 			break
 		}
-		if !isZeroOrMore(d.t) {
+		// Media can have several c= lines, e.g. for layered
+		// multicast, see RFC 4566 Section 5.7.
+		if !isZeroOrMore(d.t) && d.t != TypeConnectionData {
 			d.sPos++
 		}
-		if err := d.decodeField(m); err != nil {
+		if skip, err := d.checkOrder(); err != nil {
 			return errors.Wrap(err, "failed to decode field")
+		} else if skip {
+			continue
+		}
+		if err := d.decodeField(m); err != nil {
+			if err = d.fail(errorCode(err), err); err != nil {
+				return errors.Wrap(err, "failed to decode field")
+			}
 		}
 	}
 	m.Medias = append(m.Medias, d.m)
@@ -289,7 +615,7 @@ func (d *Decoder) decodeMedia(m *Message) error {
 }
 
 func (d *Decoder) decodeVersion(m *Message) error {
-	n, err := strconv.Atoi(string(d.v))
+	n, err := strconv.Atoi(b2s(d.v))
 	if err != nil {
 		return errors.Wrap(err, "failed to parse version")
 	}
@@ -302,6 +628,10 @@ func addAttribute(a Attributes, k, v string) Attributes {
 }
 
 func (d *Decoder) decodeAttribute(m *Message) error {
+	d.attrs++
+	if err := checkLimit("Attributes", d.attrs, d.limits.Attributes); err != nil {
+		return errors.Wrap(err, "failed to decode attribute")
+	}
 	k, v, err := d.decodeKV()
 	if err != nil {
 		return errors.Wrap(err, "failed to decode attribute")
@@ -316,31 +646,40 @@ func (d *Decoder) decodeAttribute(m *Message) error {
 }
 
 func (d *Decoder) decodeSessionName(m *Message) error {
-	m.Name = string(d.v)
+	d.decodeString(d.v, &m.Name)
 	return nil
 }
 
 func (d *Decoder) decodeSessionInfo(m *Message) error {
 	if d.section == sectionMedia {
-		d.m.Title = string(d.v)
+		d.decodeString(d.v, &d.m.Title)
 	} else {
-		m.Info = string(d.v)
+		d.decodeString(d.v, &m.Info)
 	}
 	return nil
 }
 
+func (d *Decoder) decodeAddress(list []Address) []Address {
+	var a Address
+	address, name, angle := splitAddress(d.v)
+	a.AngleBrackets = angle
+	d.decodeString(address, &a.Address)
+	d.decodeString(name, &a.Name)
+	return append(list, a)
+}
+
 func (d *Decoder) decodeEmail(m *Message) error {
-	m.Email = string(d.v)
+	m.Emails = d.decodeAddress(m.Emails)
 	return nil
 }
 
 func (d *Decoder) decodePhone(m *Message) error {
-	m.Phone = string(d.v)
+	m.Phones = d.decodeAddress(m.Phones)
 	return nil
 }
 
 func (d *Decoder) decodeURI(m *Message) error {
-	m.URI = string(d.v)
+	d.decodeString(d.v, &m.URI)
 	return nil
 }
 
@@ -349,6 +688,10 @@ func (d *Decoder) decodeEncryption(m *Message) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to decode encryption")
 	}
+	if d.opts.Spec == RFC8866 {
+		err = newSectionDecodeError(d.section, "encryption key is obsolete")
+		d.warns = append(d.warns, d.fieldError(CodeObsoleteField, err))
+	}
 	e := Encryption{
 		Key:    v,
 		Method: k,
@@ -365,22 +708,82 @@ func (d *Decoder) decodeEncryption(m *Message) error {
 // ErrFailedToDecodeIP means that decoder failed to parse IP.
 var ErrFailedToDecodeIP = errors.New("invalid IP")
 
+// decodeIP decodes v, reusing memory of dst if it is blank IP with
+// enough capacity, e.g. after Message.Reset.
 func decodeIP(dst net.IP, v []byte) (net.IP, error) {
+	if len(dst) != 0 || cap(dst) < net.IPv6len {
+		dst = nil
+	}
+	if ip, ok := parseIPv4(dst, v); ok {
+		return ip, nil
+	}
 	// ALLOCATIONS: suboptimal.
-	ip := net.ParseIP(string(v))
+	ip := net.ParseIP(b2s(v))
 	if ip == nil {
 		return dst, ErrFailedToDecodeIP
 	}
 	return ip, nil
 }
 
-func decodeByte(dst []byte) (byte, error) {
-	// ALLOCATIONS: suboptimal.
-	n, err := strconv.ParseInt(string(dst), 10, 16)
-	if err != nil {
-		return 0, err
+var v4InV6Prefix = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff}
+
+// parseIPv4 parses dotted decimal IPv4 address to dst in 16-byte
+// form, just like net.ParseIP.
+func parseIPv4(dst net.IP, v []byte) (net.IP, bool) {
+	var (
+		ip    [net.IPv4len]byte
+		octet int
+	)
+	for i := 0; i < net.IPv4len; i++ {
+		if i > 0 {
+			if len(v) == 0 || v[0] != '.' {
+				return nil, false
+			}
+			v = v[1:]
+		}
+		n := 0
+		for n < len(v) && n < 4 && v[n] >= '0' && v[n] <= '9' {
+			n++
+		}
+		if n == 0 || n > 3 || (n > 1 && v[0] == '0') {
+			// No digits, too many digits or leading zero.
+			return nil, false
+		}
+		octet = 0
+		for _, c := range v[:n] {
+			octet = octet*10 + int(c-'0')
+		}
+		if octet > 0xff {
+			return nil, false
+		}
+		ip[i] = byte(octet)
+		v = v[n:]
 	}
-	return byte(n), err
+	if len(v) != 0 {
+		return nil, false
+	}
+	dst = append(dst[:0], v4InV6Prefix...)
+	return append(dst, ip[:]...), true
+}
+
+// cutByte slices b around first instance of c, returning b and nil
+// if there is no c in b.
+func cutByte(b []byte, c byte) (before, after []byte) {
+	if i := bytes.IndexByte(b, c); i >= 0 {
+		return b[:i], b[i+1:]
+	}
+	return b, nil
+}
+
+// decodeBounded decodes integer in [min, max] range from v to i.
+func decodeBounded(v []byte, min, max int, i *int) error {
+	if err := decodeInt(v, i); err != nil {
+		return err
+	}
+	if *i < min || *i > max {
+		return &strconv.NumError{Func: "decodeBounded", Num: string(v), Err: strconv.ErrRange}
+	}
+	return nil
 }
 
 func isIPv4(ip net.IP) bool {
@@ -394,25 +797,25 @@ func (d *Decoder) decodeConnectionData(m *Message) error {
 		netType           []byte
 		addressType       []byte
 		connectionAddress []byte
-		subField          int
+		addressStart      int
 		err               error
 	)
-	for _, v := range d.v {
-		if v == fieldsDelimiter {
-			subField++
-			continue
-		}
-		switch subField {
-		case 0:
-			netType = append(netType, v)
-		case 1:
-			addressType = append(addressType, v)
-		case 2:
-			connectionAddress = append(connectionAddress, v)
-		default:
-			err = d.newFieldError("unexpected subfield count")
-			return errors.Wrap(err, "failed to decode connection data")
-		}
+	p, err := d.subfields()
+	if err != nil {
+		return errors.Wrap(err, "failed to decode connection data")
+	}
+	if len(p) > 3 && len(p[3]) > 0 {
+		d.col = d.offset(p[3])
+		err = d.newFieldError("unexpected subfield count")
+		return errors.Wrap(err, "failed to decode connection data")
+	}
+	netType = p[0]
+	if len(p) > 1 {
+		addressType = p[1]
+	}
+	if len(p) > 2 {
+		connectionAddress = p[2]
+		addressStart = d.offset(p[2])
 	}
 	if len(netType) == 0 {
 		err = d.newFieldError("nettype is empty")
@@ -426,103 +829,108 @@ func (d *Decoder) decodeConnectionData(m *Message) error {
 		err := d.newFieldError("connection-address is empty")
 		return errors.Wrap(err, "failed to decode connection data")
 	}
-	switch d.section {
-	case sectionMedia:
-		d.m.Connection.AddressType = string(addressType)
-		d.m.Connection.NetworkType = string(netType)
-	case sectionSession:
-		m.Connection.AddressType = string(addressType)
-		m.Connection.NetworkType = string(netType)
-	}
-	// Decoding address.
-	// <base multicast address>[/<ttl>]/<number of addresses>
-	var (
-		base   []byte
-		first  []byte
-		second []byte
-	)
-	subField = 0
-	for _, v := range connectionAddress {
-		if v == '/' {
-			subField++
-			continue
-		}
-		switch subField {
-		case 0:
-			base = append(base, v)
-		case 1:
-			first = append(first, v)
-		case 2:
-			second = append(second, v)
-		default:
-			err = d.newFieldError("unexpected fourth element in address")
-			return errors.Wrap(err, "failed to decode connection data")
-		}
+	c := &m.Connection
+	if d.section == sectionMedia {
+		c = d.m.addConnection()
 	}
-	switch d.section {
-	case sectionMedia:
-		d.m.Connection.IP, err = decodeIP(m.Connection.IP, base)
-	case sectionSession:
-		m.Connection.IP, err = decodeIP(m.Connection.IP, base)
+	d.decodeString(addressType, &c.AddressType)
+	d.decodeString(netType, &c.NetworkType)
+	d.col = addressStart
+	if c.NetworkType != NetworkTypeInternet {
+		return d.decodeNetworkAddress(c, connectionAddress)
 	}
+	host, err := decodeInternetAddress(c, connectionAddress)
 	if err != nil {
 		return errors.Wrap(err, "failed to decode connection data")
 	}
+	d.decodeString(host, &c.Host)
+	return nil
+}
 
-	var isV4 bool
-	switch d.section {
-	case sectionMedia:
-		isV4 = isIPv4(d.m.Connection.IP)
-	case sectionSession:
-		isV4 = isIPv4(m.Connection.IP)
-	}
-	if len(second) > 0 {
-		if !isV4 {
-			err := d.newFieldError("unexpected TTL for IPv6")
-			return errors.Wrap(err, "failed to decode connection data")
-		}
-		switch d.section {
-		case sectionMedia:
-			d.m.Connection.TTL, err = decodeByte(first)
-		case sectionSession:
-			m.Connection.TTL, err = decodeByte(first)
-		}
-		if err != nil {
-			return errors.Wrap(err, "failed to decode connection data")
-		}
-		switch d.section {
-		case sectionMedia:
-			d.m.Connection.Addresses, err = decodeByte(second)
-		case sectionSession:
-			m.Connection.Addresses, err = decodeByte(second)
-		}
-		if err != nil {
-			return errors.Wrap(err, "failed to decode connection data")
-		}
-	} else if len(first) > 0 {
-		if isV4 {
-			switch d.section {
-			case sectionMedia:
-				d.m.Connection.TTL, err = decodeByte(first)
-			case sectionSession:
-				m.Connection.TTL, err = decodeByte(first)
-			}
-		} else {
-			switch d.section {
-			case sectionMedia:
-				d.m.Connection.Addresses, err = decodeByte(second)
-			case sectionSession:
-				m.Connection.Addresses, err = decodeByte(second)
-			}
+// decodeNetworkAddress decodes <connection-address> of network types
+// other than IN using registered NetworkType.
+func (d *Decoder) decodeNetworkAddress(c *ConnectionData, v []byte) error {
+	t, ok := LookupNetworkType(c.NetworkType)
+	if ok {
+		return errors.Wrap(t.DecodeAddress(c, v), "failed to decode connection data")
+	}
+	if d.opts.Strict {
+		msg := fmt.Sprintf("unknown nettype %s", c.NetworkType)
+		err := newSectionDecodeError(d.section, msg)
+		return errors.Wrap(err, "failed to decode connection data")
+	}
+	var raw string
+	d.decodeString(v, &raw)
+	c.Address = RawAddress(raw)
+	return nil
+}
+
+// decodeInternetAddress decodes <connection-address> v of IN network
+// type to c, returning host if v is FQDN.
+func decodeInternetAddress(c *ConnectionData, v []byte) (host []byte, err error) {
+	// <base multicast address>[/<ttl>]/<number of addresses>
+	base, rest := cutByte(v, '/')
+	first, rest := cutByte(rest, '/')
+	second, rest := cutByte(rest, '/')
+	if len(rest) > 0 {
+		return nil, newDecodeError("connection-address", "unexpected fourth element in address")
+	}
+	c.IP, err = decodeIP(c.IP, base)
+	if err != nil && len(first) == 0 && isFQDN(base) {
+		// Unicast address can be FQDN.
+		c.IP = c.IP[:0]
+		return base, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// IP4 multicast: <base>/<ttl>[/<number of addresses>],
+	// IP6 multicast: <base>[/<number of addresses>], without TTL.
+	ttl, addresses := first, second
+	if !isIPv4(c.IP) {
+		if len(second) > 0 {
+			return nil, newDecodeError("connection-address", "unexpected TTL for IPv6")
+		}
+		ttl, addresses = nil, first
+	}
+	if len(ttl) > 0 {
+		if err = decodeBounded(ttl, 0, MaxTTL, &c.TTL); err != nil {
+			return nil, errors.Wrapf(err, "bad TTL <%s> at <%s>", b2s(ttl), b2s(v))
 		}
-		if err != nil {
-			msg := fmt.Sprintf("bad connection data <%s> at <%s>",
-				b2s(first), b2s(connectionAddress),
+	}
+	if len(addresses) > 0 {
+		if err = decodeBounded(addresses, 1, MaxAddresses, &c.Addresses); err != nil {
+			return nil, errors.Wrapf(err, "bad number of addresses <%s> at <%s>",
+				b2s(addresses), b2s(v),
 			)
-			return errors.Wrap(err, msg)
 		}
 	}
-	return nil
+	return nil, nil
+}
+
+// isFQDN returns true if v is fully qualified domain name, as defined
+// in RFC 4566 Section 9:
+//
+//	FQDN = 4*(alpha-numeric / "-" / ".")
+//
+// Values that consist only of digits and dots are malformed IPv4
+// addresses and are not considered as FQDN.
+func isFQDN(v []byte) bool {
+	if len(v) < 4 {
+		return false
+	}
+	isNumeric := true
+	for _, c := range v {
+		switch {
+		case c >= '0' && c <= '9', c == '.':
+			continue
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '-':
+			isNumeric = false
+		default:
+			return false
+		}
+	}
+	return !isNumeric
 }
 
 func (d *Decoder) decodeBandwidth(m *Message) error {
@@ -535,60 +943,49 @@ func (d *Decoder) decodeBandwidth(m *Message) error {
 		err := newSectionDecodeError(d.section, msg)
 		return errors.Wrap(err, "failed to decode bandwidth")
 	}
-	var (
-		t BandwidthType
-		n int
-	)
-	switch bandWidthType := BandwidthType(k); bandWidthType {
-	case BandwidthApplicationSpecific, BandwidthConferenceTotal, BandwidthApplicationSpecificTransportIndependent:
-		t = bandWidthType
-	default:
+	// Unknown types are decoded too, because RFC 4566 Section 5.8
+	// requires to ignore them instead of failing.
+	if i := tokenError(d.v[:len(k)]); i >= 0 {
+		d.col = i
 		msg := fmt.Sprintf("bad bandwidth type %s", k)
 		err = newSectionDecodeError(d.section, msg)
 		return errors.Wrap(err, "failed to decode bandwidth")
 	}
-	if n, err = strconv.Atoi(v); err != nil {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		d.col = len(k) + 1
 		return errors.Wrap(err, "failed to convert decode bandwidth")
 	}
+	b := Bandwidth{Type: BandwidthType(k), Value: n}
 	if d.section == sectionMedia {
-		if d.m.Bandwidths == nil {
-			d.m.Bandwidths = make(Bandwidths)
-		}
-		d.m.Bandwidths[t] = n
+		d.m.Bandwidths = append(d.m.Bandwidths, b)
 	} else {
-		if m.Bandwidths == nil {
-			m.Bandwidths = make(Bandwidths)
-		}
-		m.Bandwidths[t] = n
+		m.Bandwidths = append(m.Bandwidths, b)
 	}
 	return nil
 }
 
 func parseNTP(v []byte) (uint64, error) {
-	return strconv.ParseUint(string(v), 10, 64)
+	return strconv.ParseUint(b2s(v), 10, 64)
 }
 
 func (d *Decoder) decodeTimingField(m *Message) error {
 	var (
 		startV, endV []byte
-		isEndV       bool
-		err          error
 	)
-	for _, v := range d.v {
-		if v == fieldsDelimiter {
-			if isEndV {
-				msg := "unexpected second space in timing"
-				err = newSectionDecodeError(d.section, msg)
-				return errors.Wrap(err, "failed to decode timing")
-			}
-			isEndV = true
-			continue
-		}
-		if isEndV {
-			endV = append(endV, v)
-		} else {
-			startV = append(startV, v)
-		}
+	p, err := d.subfields()
+	if err != nil {
+		return errors.Wrap(err, "failed to decode timing")
+	}
+	if len(p) > 2 {
+		d.col = d.offset(p[2]) - 1
+		msg := "unexpected second space in timing"
+		err = newSectionDecodeError(d.section, msg)
+		return errors.Wrap(err, "failed to decode timing")
+	}
+	startV = p[0]
+	if len(p) > 1 {
+		endV = p[1]
 	}
 	var (
 		ntpStart, ntpEnd uint64
@@ -597,6 +994,7 @@ func (d *Decoder) decodeTimingField(m *Message) error {
 		return errors.Wrap(err, "failed to parse start time")
 	}
 	if ntpEnd, err = parseNTP(endV); err != nil {
+		d.col = d.offset(startV) + len(startV) + 1
 		return errors.Wrap(err, "failed to parse end time")
 	}
 	t := Timing{}
@@ -606,8 +1004,17 @@ func (d *Decoder) decodeTimingField(m *Message) error {
 	return nil
 }
 
-func decodeString(v []byte, s *string) {
-	*s = b2s(v)
+// decodeString sets s to string value of v that is copied unless
+// zero-copy mode is enabled.
+func (d *Decoder) decodeString(v []byte, s *string) {
+	switch {
+	case d.opts.ZeroCopy:
+		*s = b2s(v)
+	case len(v) == 0:
+		*s = blank
+	default:
+		*s = string(v)
+	}
 }
 
 func decodeInt(v []byte, i *int) error {
@@ -622,34 +1029,55 @@ func decodeInt64(v []byte, i *int64) error {
 	return err
 }
 
+// subfields splits d.v by single spaces, returning slices of d.v.
+//
+// Multiple, leading and trailing spaces are skipped with warning if
+// LenientExtraSpaces rule is relaxed.
 func (d *Decoder) subfields() ([][]byte, error) {
-	n := bytes.Count(d.v, []byte{fieldsDelimiter})
-	result := make([][]byte, n+1)
-	subField := 0
-	hitSpace := true
-	for _, v := range d.v {
-		if v == fieldsDelimiter {
-			if hitSpace {
+	// Reusing memory, so result is valid until next call.
+	result := d.fields[:0]
+	start := 0
+	warned := false
+	for i, v := range d.v {
+		if v != fieldsDelimiter {
+			continue
+		}
+		if i == start {
+			if !d.relaxed(LenientExtraSpaces) {
+				d.col = i
 				msg := "unexpected second space in subfields"
 				return nil, newSectionDecodeError(d.section, msg)
 			}
-			subField++
-			hitSpace = true
+			if !warned {
+				d.warnSpace(i)
+				warned = true
+			}
+			start = i + 1
 			continue
-		} else {
-			hitSpace = false
 		}
-
-		result[subField] = append(result[subField], v)
+		result = append(result, d.v[start:i])
+		start = i + 1
 	}
+	if start == 0 || start < len(d.v) || !d.relaxed(LenientExtraSpaces) {
+		result = append(result, d.v[start:])
+	} else if !warned {
+		d.warnSpace(start - 1)
+	}
+	d.fields = result
+	return result, nil
+}
 
-	return result[:subField+1], nil
+// warnSpace records warning about extra space at offset i of d.v.
+func (d *Decoder) warnSpace(i int) {
+	d.col = i
+	err := newSectionDecodeError(d.section, "extra space in subfields")
+	d.warns = append(d.warns, d.fieldError(CodeInvalidSyntax, err))
+	d.col = 0
 }
 
 func (d *Decoder) decodeOrigin(m *Message) error {
 	// o=0<username> 1<sess-id> 2<sess-version> 3<nettype> 4<addrtype>
 	// 5<unicast-address>
-	// ALLOCATIONS: suboptimal
 	// CPU: suboptimal
 	var (
 		err error
@@ -664,21 +1092,26 @@ func (d *Decoder) decodeOrigin(m *Message) error {
 		return errors.Wrap(err, "failed to decode origin")
 	}
 	o := m.Origin
-	decodeString(p[0], &o.Username)
+	d.decodeString(p[0], &o.Username)
 	if err = decodeInt64(p[1], &o.SessionID); err != nil {
+		d.col = d.offset(p[1])
 		return errors.Wrap(err, "failed to decode sess-id")
 	}
 	if err = decodeInt64(p[2], &o.SessionVersion); err != nil {
+		d.col = d.offset(p[2])
 		return errors.Wrap(err, "failed to decode sess-version")
 	}
-	decodeString(p[3], &o.NetworkType)
-	decodeString(p[4], &o.AddressType)
-	decodeString(p[5], &o.Address)
+	d.decodeString(p[3], &o.NetworkType)
+	d.decodeString(p[4], &o.AddressType)
+	d.decodeString(p[5], &o.Address)
 	m.Origin = o
 	return nil
 }
 
 func decodeInterval(b []byte, v *time.Duration) error {
+	if len(b) == 0 {
+		return errors.Wrap(io.ErrUnexpectedEOF, "empty interval")
+	}
 	if len(b) == 1 && b[0] == '0' {
 		*v = 0
 		return nil
@@ -733,16 +1166,26 @@ func (d *Decoder) decodeRepeatTimes(m *Message) error {
 		err = newSectionDecodeError(d.section, msg)
 		return errors.Wrap(err, "failed to decode repeat")
 	}
-	t := m.Timing[len(m.Timing)-1]
+	if err = checkLimit("Offsets", len(p)-2, d.limits.Offsets); err != nil {
+		return errors.Wrap(err, "failed to decode repeat")
+	}
+	t := &m.Timing[len(m.Timing)-1]
+	if len(t.Offsets) > 0 {
+		// Each of several "r=" fields is decoded to its own Timing.
+		m.Timing = append(m.Timing, Timing{Start: t.Start, End: t.End})
+		t = &m.Timing[len(m.Timing)-1]
+	}
 	if err = decodeInterval(p[0], &t.Repeat); err != nil {
 		return errors.Wrap(err, "failed to decode repeat interval")
 	}
 	if err = decodeInterval(p[1], &t.Active); err != nil {
+		d.col = d.offset(p[1])
 		return errors.Wrap(err, "failed to decode active duration")
 	}
 	var dd time.Duration
 	for i, pp := range p[2:] {
 		if err = decodeInterval(pp, &dd); err != nil {
+			d.col = d.offset(pp)
 			return errors.Wrapf(err, "failed to decode offset %d", i)
 		}
 		t.Offsets = append(t.Offsets, dd)
@@ -767,10 +1210,12 @@ func (d *Decoder) decodeTimeZoneAdjustments(m *Message) error {
 	}
 	for i := 0; i < len(p); i += 2 {
 		if t, err = parseNTP(p[i]); err != nil {
+			d.col = d.offset(p[i])
 			return errors.Wrap(err, "failed to decode adjustment start")
 		}
 		adjustment.Start = NTPToTime(t)
 		if err = decodeInterval(p[i+1], &adjustment.Offset); err != nil {
+			d.col = d.offset(p[i+1])
 			return errors.Wrap(err, "failed to decode offset")
 		}
 		m.TZAdjustments = append(m.TZAdjustments, adjustment)
@@ -784,6 +1229,10 @@ func (d *Decoder) decodeMediaDescription(_ *Message) error {
 		desc MediaDescription
 		err  error
 	)
+	d.medias++
+	if err = checkLimit("Medias", d.medias, d.limits.Medias); err != nil {
+		return errors.Wrap(err, "failed to decode media description")
+	}
 	p, err := d.subfields()
 	if err != nil {
 		return errors.Wrap(err, "failed to decode media description")
@@ -793,26 +1242,70 @@ func (d *Decoder) decodeMediaDescription(_ *Message) error {
 		err = newSectionDecodeError(d.section, msg)
 		return errors.Wrap(err, "failed to decode media description")
 	}
-	decodeString(p[0], &desc.Type)
+	if err = checkLimit("Formats", len(p)-3, d.limits.Formats); err != nil {
+		return errors.Wrap(err, "failed to decode media description")
+	}
+	d.decodeString(p[0], &desc.Type)
 	// port: port/ports_number
-	pp := bytes.Split(p[1], []byte{'/'})
-	if err = decodeInt(pp[0], &desc.Port); err != nil {
+	port, portsNumber := cutByte(p[1], '/')
+	if err = decodeInt(port, &desc.Port); err != nil {
+		d.col = d.offset(p[1])
 		return errors.Wrap(err, "failed to decode port")
 	}
-	if len(pp) > 1 {
-		if err = decodeInt(pp[1], &desc.PortsNumber); err != nil {
+	if portsNumber != nil {
+		if err = decodeInt(portsNumber, &desc.PortsNumber); err != nil {
+			d.col = d.offset(portsNumber)
 			return errors.Wrap(err, "failed to decode ports number")
 		}
 	}
-	decodeString(p[2], &desc.Protocol)
+	d.decodeString(p[2], &desc.Protocol)
+	if len(p) > 3 {
+		// Reusing memory of formats, see Message.Reset.
+		desc.Formats = d.m.Description.Formats[:0]
+	}
 	for _, rawFormat := range p[3:] {
-		desc.Formats = append(desc.Formats, string(rawFormat))
+		var format string
+		d.decodeString(rawFormat, &format)
+		desc.Formats = append(desc.Formats, format)
 	}
 	d.m.Description = desc
 	return nil
 }
 
+// checkGrammar checks value of current line against grammar of
+// RFC 8866 if it is selected.
+func (d *Decoder) checkGrammar() error {
+	if d.opts.Spec != RFC8866 {
+		return nil
+	}
+	switch d.t {
+	case TypeAttribute:
+		// attribute = (attribute-name ":" attribute-value) / attribute-name
+		name, value := d.v, []byte(nil)
+		if i := bytes.IndexByte(d.v, attributesDelimiter); i >= 0 {
+			name, value = d.v[:i], d.v[i+1:]
+		}
+		if i := tokenError(name); i >= 0 {
+			d.col = i
+			return newSectionDecodeError(d.section, "attribute name is not a token")
+		}
+		if i := byteStringError(value); len(value) > 0 && i >= 0 {
+			d.col = len(name) + 1 + i
+			return newSectionDecodeError(d.section, "attribute value is not a byte-string")
+		}
+	case TypeSessionName, TypeSessionInformation:
+		if i := byteStringError(d.v); i >= 0 {
+			d.col = i
+			return newSectionDecodeError(d.section, "text is not a byte-string")
+		}
+	}
+	return nil
+}
+
 func (d *Decoder) decodeField(m *Message) error {
+	if err := d.checkGrammar(); err != nil {
+		return errors.Wrap(err, "invalid grammar")
+	}
 	switch d.t {
 	case TypeProtocolVersion:
 		return d.decodeVersion(m)
@@ -858,12 +1351,17 @@ func canSkip(err error) bool {
 func (d *Decoder) decodeSession(m *Message) error {
 	d.sPos = 0
 	d.section = sectionSession
+	d.seen = 0
+	d.attrs = 0
 	for d.next() {
-		if err := isExpected(d.t, d.section, d.sPos); err != nil {
+		if err := isExpected(d.opts.Spec, d.t, d.section, d.sPos); err != nil {
 			if canSkip(err) {
 				continue
 			}
-			return errors.Wrap(err, "decode failed")
+			if err = d.fail(CodeUnexpectedField, err); err != nil {
+				return errors.Wrap(err, "decode failed")
+			}
+			continue
 		}
 		if !isZeroOrMore(d.t) {
 			d.sPos++
@@ -886,8 +1384,15 @@ func (d *Decoder) decodeSession(m *Message) error {
 			d.sPos = oldPosition
 			d.section = sectionSession
 		default:
-			if err := d.decodeField(m); err != nil {
+			if skip, err := d.checkOrder(); err != nil {
 				return errors.Wrap(err, "failed to decode field")
+			} else if skip {
+				continue
+			}
+			if err := d.decodeField(m); err != nil {
+				if err = d.fail(errorCode(err), err); err != nil {
+					return errors.Wrap(err, "failed to decode field")
+				}
 			}
 		}
 	}
@@ -895,20 +1400,121 @@ func (d *Decoder) decodeSession(m *Message) error {
 	if m.Origin.Address == "" {
 		msg := fmt.Sprintf("origin address not set")
 		err := newSectionDecodeError(sectionSession, msg)
-		return errors.Wrap(err, "failed to decode message")
+		if err := d.failMissing(TypeOrigin, err); err != nil {
+			return errors.Wrap(err, "failed to decode message")
+		}
 	}
 	if m.Name == "" {
 		msg := fmt.Sprintf("session name not set")
 		err := newSectionDecodeError(sectionSession, msg)
-		return errors.Wrap(err, "failed to decode message")
+		if err := d.failMissing(TypeSessionName, err); err != nil {
+			return errors.Wrap(err, "failed to decode message")
+		}
+	}
+	if d.timings == 0 {
+		msg := fmt.Sprintf("time description not set")
+		err := newSectionDecodeError(sectionSession, msg)
+		e := missingError(TypeTiming, err)
+		if err := d.relax(LenientMissingTiming, e); err != nil {
+			return errors.Wrap(err, "failed to decode message")
+		}
 	}
 
 	return nil
 }
 
 // Decode message from session.
+//
+// Returned error is wrapped *FieldError or ErrorList if
+// DecoderOptions.AllErrors is set.
 func (d *Decoder) Decode(m *Message) error {
-	return d.decodeSession(m)
+	d.msg = m
+	defer func() {
+		d.msg = nil
+	}()
+	medias := len(m.Medias)
+	d.errs = nil
+	d.warns = nil
+	d.timings = 0
+	d.medias = 0
+	if err := d.checkLines(); err != nil {
+		return errors.Wrap(err, "failed to decode message")
+	}
+	if err := d.decodeSession(m); err != nil {
+		return err
+	}
+	if len(d.errs) > 0 {
+		return d.errs
+	}
+	if d.opts.Lossless {
+		d.captureRaw(m, m.Medias[medias:])
+	}
+	return nil
+}
+
+// DecodeMedia decodes session that consists of single media section,
+// starting from "m=" line, to m. See SessionIndex. Lines are captured
+// to m.Raw if DecoderOptions.Lossless is set.
+//
+// Memory of m is reused, so its previous values must not be used
+// after decoding, like after Message.Reset.
+func (d *Decoder) DecodeMedia(m *Media) error {
+	if len(d.s) == 0 || d.s[0].Type != TypeMediaDescription {
+		msg := "session does not start with media description"
+		return newSectionDecodeError(sectionMedia, msg)
+	}
+	// Decoding as the only media of message, reusing memory of m.
+	medias := [1]Media{*m}
+	msg := &Message{Medias: medias[:0]}
+	d.msg = msg
+	defer func() {
+		d.msg = nil
+	}()
+	d.errs = nil
+	d.warns = nil
+	if err := d.checkLines(); err != nil {
+		return errors.Wrap(err, "failed to decode media")
+	}
+	if err := d.decodeMedia(msg); err != nil {
+		return err
+	}
+	if d.pos < len(d.s) {
+		d.next()
+		err := newSectionDecodeError(sectionMedia, "unexpected second media description")
+		if err := d.fail(CodeUnexpectedField, err); err != nil {
+			return errors.Wrap(err, "failed to decode media")
+		}
+	}
+	if len(d.errs) > 0 {
+		return d.errs
+	}
+	if d.opts.Lossless {
+		d.captureRaw(msg, msg.Medias)
+	}
+	*m = msg.Medias[0]
+	return nil
+}
+
+// captureRaw copies lines of session section to m.Raw and lines of
+// every media section to corresponding element of medias.
+func (d *Decoder) captureRaw(m *Message, medias Medias) {
+	var (
+		raw   = &m.Raw
+		media = -1
+	)
+	raw.Lines = raw.Lines[:0]
+	for _, l := range d.s {
+		if l.Type == TypeMediaDescription {
+			media++
+			raw = &medias[media].Raw
+			raw.Lines = raw.Lines[:0]
+		}
+		raw.Lines = raw.Lines.append(l.Type, l.Value)
+	}
+	m.Raw.canonical = m.appendSessionSection(nil)
+	for i := range medias {
+		medias[i].Raw.canonical = medias[i].appendSection(nil)
+	}
 }
 
 // b2s converts byte slice to a string without memory allocation.
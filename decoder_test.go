@@ -1,11 +1,15 @@
 package sdp
 
 import (
+	"bytes"
 	"fmt"
 	"log"
 	"net"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 func TestDecodeInterval(t *testing.T) {
@@ -23,6 +27,7 @@ func TestDecodeInterval(t *testing.T) {
 		{"5", time.Second * 5, false},
 		{"s", time.Second * 0, true},
 		{"zs", time.Second * 0, true},
+		{"", time.Second * 0, true},
 	}
 	for _, dtt := range dt {
 		t.Run(dtt.in, func(t *testing.T) {
@@ -74,8 +79,8 @@ func TestDecoder_Decode(t *testing.T) {
 				log.Println(m.Medias[0].Attributes)
 				t.Error("rtpmap", m.Medias[1].Attributes.Value("rtpmap"))
 			}
-			if m.Bandwidths[BandwidthConferenceTotal] != 154798 {
-				t.Error("bandwidth bad value", m.Bandwidths[BandwidthConferenceTotal])
+			if m.Bandwidths.Value(BandwidthConferenceTotal) != 154798 {
+				t.Error("bandwidth bad value", m.Bandwidths.Value(BandwidthConferenceTotal))
 			}
 			expectedEncryption := Encryption{"clear", "ab8c4df8b8f4as8v8iuy8re"}
 			if m.Encryption != expectedEncryption {
@@ -92,6 +97,16 @@ func TestDecoder_Decode(t *testing.T) {
 			if m.End() != NTPToTime(2873404696) {
 				t.Error(m.End(), "!=", NTPToTime(2873404696))
 			}
+			tExpected := Timing{
+				Start:   NTPToTime(2873397496),
+				End:     NTPToTime(2873404696),
+				Repeat:  7 * 24 * time.Hour,
+				Active:  time.Hour,
+				Offsets: []time.Duration{0, 25 * time.Hour},
+			}
+			if len(m.Timing) != 1 || !m.Timing[0].Equal(tExpected) {
+				t.Error(m.Timing, "!=", tExpected)
+			}
 			cExpected := ConnectionData{
 				IP:          net.ParseIP("224.2.17.12"),
 				TTL:         127,
@@ -368,12 +383,104 @@ func TestDecoder_ExMediaConnection(t *testing.T) {
 			NetworkType: "IN",
 			AddressType: "IP4",
 		}
-		if got := m.Medias[0].Connection; !cExpected.Equal(got) {
+		if len(m.Medias[0].Connections) != 1 {
+			t.Fatalf("unexpected connections %v", m.Medias[0].Connections)
+		}
+		if got := m.Medias[0].Connections[0]; !cExpected.Equal(got) {
 			t.Errorf("%s (got) != %s (expected)", got, cExpected)
 		}
 	}
 }
 
+func TestDecoder_MediaConnectionMulticast(t *testing.T) {
+	tData := loadData(t, "sdp_session_ex_mediac", testNL)
+	for _, tc := range []struct {
+		name       string
+		connection string
+		expected   ConnectionData
+		scope      MulticastScope
+		ok         bool
+	}{
+		{
+			name:       "IP4",
+			connection: "IN IP4 224.2.1.1/127",
+			expected:   ConnectionData{IP: net.ParseIP("224.2.1.1"), TTL: 127},
+			scope:      ScopeGlobal,
+			ok:         true,
+		},
+		{
+			name:       "IP4Addresses",
+			connection: "IN IP4 239.255.1.1/127/3",
+			expected:   ConnectionData{IP: net.ParseIP("239.255.1.1"), TTL: 127, Addresses: 3},
+			scope:      ScopeSiteLocal,
+			ok:         true,
+		},
+		{
+			name:       "IP4ManyAddresses",
+			connection: "IN IP4 224.2.1.1/0/300",
+			expected:   ConnectionData{IP: net.ParseIP("224.2.1.1"), Addresses: 300},
+			scope:      ScopeGlobal,
+			ok:         true,
+		},
+		{
+			name:       "IP6",
+			connection: "IN IP6 FF15::101",
+			expected:   ConnectionData{IP: net.ParseIP("FF15::101")},
+			scope:      ScopeSiteLocal,
+			ok:         true,
+		},
+		{
+			name:       "IP6Addresses",
+			connection: "IN IP6 FF1E::101/3",
+			expected:   ConnectionData{IP: net.ParseIP("FF1E::101"), Addresses: 3},
+			scope:      ScopeGlobal,
+			ok:         true,
+		},
+		{
+			name:       "IP6Unicast",
+			connection: "IN IP6 2001:DB8::2",
+			expected:   ConnectionData{IP: net.ParseIP("2001:db8::2")},
+			scope:      ScopeNone,
+			ok:         true,
+		},
+		{name: "IP6TTL", connection: "IN IP6 FF15::101/127/3"},
+		{name: "IP6BadAddresses", connection: "IN IP6 FF15::101/x"},
+		{name: "IP4BigTTL", connection: "IN IP4 224.2.1.1/256"},
+		{name: "IP4NegativeTTL", connection: "IN IP4 224.2.1.1/-1"},
+		{name: "IP4ZeroAddresses", connection: "IN IP4 224.2.1.1/127/0"},
+		{name: "IP4TooManyAddresses", connection: "IN IP4 224.2.1.1/127/65537"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := bytes.Replace(tData, []byte("c=IN IP4 0.0.0.0"), []byte("c="+tc.connection), 1)
+			m, err := Decode(data)
+			if !tc.ok {
+				if !errors.Is(err, ErrInvalidSyntax) && !errors.Is(err, ErrInvalidNumber) {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := m.Medias[0].Connections[0]
+			tc.expected.NetworkType = "IN"
+			tc.expected.AddressType = tc.connection[3:6]
+			if !got.Equal(tc.expected) {
+				t.Errorf("%+v != %+v", got, tc.expected)
+			}
+			if got.Scope() != tc.scope {
+				t.Errorf("scope %s != %s", got.Scope(), tc.scope)
+			}
+			if s := got.NetworkType + " " + got.AddressType + " " + got.ConnectionAddress(); s != tc.connection {
+				t.Errorf("encoded %q != %q", s, tc.connection)
+			}
+			if err = m.Validate(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
 func TestDecoder_NoMediaFmt(t *testing.T) {
 	m := new(Message)
 	tData := loadData(t, "sdp_session_no_media_fmt", testNL)
@@ -399,7 +506,7 @@ func TestSectionOverflows(t *testing.T) {
 	})
 	t.Run("Ordering", func(t *testing.T) {
 		defer mustOverflow(t)
-		fmt.Print(getOrdering(section(123)))
+		fmt.Print(getOrdering(RFC4566, section(123)))
 	})
 }
 
@@ -438,6 +545,102 @@ func TestDecodeLastTiming(t *testing.T) {
 	}
 }
 
+func TestDecoder_TimeZonesOrder(t *testing.T) {
+	decode := func(t *testing.T, name string, opts DecoderOptions) (*Message, Decoder, error) {
+		t.Helper()
+		session, err := DecodeSession(loadData(t, name, testNL), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := NewDecoderWithOptions(session, opts)
+		m := new(Message)
+		return m, d, d.Decode(m)
+	}
+	expected, d, err := decode(t, "sdp_session_ex_zones_after_timing", DecoderOptions{Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Warnings()) != 0 {
+		t.Errorf("unexpected warnings: %v", d.Warnings())
+	}
+	if len(expected.TZAdjustments) != 2 || expected.TZAdjustments[0].Offset != -time.Hour {
+		t.Errorf("unexpected adjustments: %v", expected.TZAdjustments)
+	}
+	if b := expected.Append(nil).AppendTo(nil); !bytes.Equal(b, loadData(t, "sdp_session_ex_zones_after_timing", testCRNL)) {
+		t.Errorf("unexpected encoding:\n%s", b)
+	}
+	t.Run("BeforeTiming", func(t *testing.T) {
+		m, d, err := decode(t, "sdp_session_ex_full", DecoderOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		warnings := d.Warnings()
+		if len(warnings) != 1 || warnings[0].Line != 10 || warnings[0].Code != CodeUnexpectedField {
+			t.Errorf("unexpected warnings: %v", warnings)
+		}
+		if !m.Equal(expected) {
+			t.Errorf("%+v != %+v", m, expected)
+		}
+		if _, _, err = decode(t, "sdp_session_ex_full", DecoderOptions{Strict: true}); !errors.Is(err, ErrUnexpectedField) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("Multiple", func(t *testing.T) {
+		session, err := DecodeSession([]byte("v=0\no=- 1 2 IN IP4 127.0.0.1\ns=-\n"+
+			"z=2882844526 -1h\nt=0 0\nz=2882844526 -1h\n"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := NewDecoder(session)
+		err = d.Decode(new(Message))
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Line != 6 || fieldErr.Code != CodeUnexpectedField {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestDecoder_MultipleRepeats(t *testing.T) {
+	tData := []byte("v=0\r\no=- 1 2 IN IP4 127.0.0.1\r\ns=-\r\n" +
+		"t=2873397496 2873404696\r\nr=7d 1h 0 25h\r\nr=1d 2h 0\r\nz=2882844526 -1h\r\n")
+	session, err := DecodeSession(tData, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewDecoder(session)
+	m := new(Message)
+	if err = d.Decode(m); err != nil {
+		t.Fatal(err)
+	}
+	expected := []Timing{
+		{
+			Start:   NTPToTime(2873397496),
+			End:     NTPToTime(2873404696),
+			Repeat:  7 * 24 * time.Hour,
+			Active:  time.Hour,
+			Offsets: []time.Duration{0, 25 * time.Hour},
+		},
+		{
+			Start:   NTPToTime(2873397496),
+			End:     NTPToTime(2873404696),
+			Repeat:  24 * time.Hour,
+			Active:  2 * time.Hour,
+			Offsets: []time.Duration{0},
+		},
+	}
+	if len(m.Timing) != len(expected) {
+		t.Fatalf("unexpected timing: %v", m.Timing)
+	}
+	for i := range expected {
+		if !m.Timing[i].Equal(expected[i]) {
+			t.Errorf("timing %d: %v != %v", i, m.Timing[i], expected[i])
+		}
+	}
+	if b := m.Append(nil).AppendTo(nil); !bytes.Equal(b, tData) {
+		t.Errorf("%q != %q", b, tData)
+	}
+}
+
 func TestDecodeUnknownType(t *testing.T) {
 	m := new(Message)
 	tData := loadData(t, "sdp_session_ex_media_unknown_type", testNL)
@@ -450,3 +653,494 @@ func TestDecodeUnknownType(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestDecoder_Lossless(t *testing.T) {
+	for _, name := range []string{
+		"sdp_session_ex_full",
+		"sdp_session_ex_media_unknown_type",
+		"spd_session_ex_webrtc1",
+	} {
+		t.Run(name, func(t *testing.T) {
+			tData := loadData(t, name, testCRNL)
+			session, err := DecodeSession(tData, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m := new(Message)
+			d := NewDecoderWithOptions(session, DecoderOptions{Lossless: true})
+			if err = d.Decode(m); err != nil {
+				t.Fatal(err)
+			}
+			// Corrupting session to check that lines are copied.
+			for i := range session {
+				for j := range session[i].Value {
+					session[i].Value[j] = 'x'
+				}
+			}
+			if out := m.Append(nil).AppendTo(nil); !bytes.Equal(out, tData) {
+				t.Errorf("not equal:\n%s\n!=\n%s", out, tData)
+			}
+		})
+	}
+}
+
+func TestDecoder_LosslessModified(t *testing.T) {
+	tData := loadData(t, "sdp_session_ex_media_unknown_type", testNL)
+	session, err := DecodeSession(tData, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := new(Message)
+	d := NewDecoderWithOptions(session, DecoderOptions{Lossless: true})
+	if err = d.Decode(m); err != nil {
+		t.Fatal(err)
+	}
+	m.Name = "modified"
+	m.Medias[0].AddFlag("sendonly")
+	expected := `v=0
+α=vαlue
+o=- 4466110607526902804 2 IN IP4 127.0.0.1
+s=modified
+e=test@test.com
+c=IN IP4 224.2.17.12/127
+b=CT:154798
+t=2873397496 2873404696
+@=vαlue
+z=2882844526 -1h 2898848070 0
+ü=vαlue
+m=video 51372 RTP/AVP 99
+a=rtpmap:99 h263-1998/90000
+ü=vαlue
+a=sendonly
+`
+	out := (EncodeOptions{LF: true}).AppendSession(nil, m.Append(nil))
+	if string(out) != expected {
+		t.Errorf("unexpected result:\n%s", out)
+	}
+	t.Run("Default", func(t *testing.T) {
+		m := new(Message)
+		d := NewDecoder(session)
+		if err = d.Decode(m); err != nil {
+			t.Fatal(err)
+		}
+		if len(m.Raw.Lines) != 0 || len(m.Medias[0].Raw.Lines) != 0 {
+			t.Error("raw lines should not be captured")
+		}
+	})
+}
+
+func TestDecoder_CopyStrings(t *testing.T) {
+	tData := loadData(t, "spd_session_ex_webrtc1", testNL)
+	expected, err := Decode(tData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupt := func(s Session) {
+		for i := range s {
+			for j := range s[i].Value {
+				s[i].Value[j] = 'x'
+			}
+		}
+	}
+	t.Run("Mutate", func(t *testing.T) {
+		s, err := DecodeSession(tData, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := new(Message)
+		d := NewDecoder(s)
+		if err = d.Decode(m); err != nil {
+			t.Fatal(err)
+		}
+		corrupt(s)
+		if !reflect.DeepEqual(m, expected) {
+			t.Error("message corrupted after session mutation")
+		}
+	})
+	t.Run("Reuse", func(t *testing.T) {
+		s, err := DecodeSession(tData, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := new(Message)
+		d := NewDecoder(s)
+		if err = d.Decode(m); err != nil {
+			t.Fatal(err)
+		}
+		// Reusing lines memory as suggested by DecodeSession docs.
+		other := loadData(t, "sdp_session_ex_full", testNL)
+		if _, err = DecodeSession(other, s.reset()); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(m, expected) {
+			t.Error("message corrupted after session reuse")
+		}
+	})
+	t.Run("MessageReset", func(t *testing.T) {
+		s, err := DecodeSession(tData, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := new(Message)
+		d := NewDecoder(s)
+		if err = d.Decode(m); err != nil {
+			t.Fatal(err)
+		}
+		name, mid := m.Name, m.Medias[0].Attribute("mid")
+		// Decoding other message to reset m should not change strings
+		// that were decoded before.
+		other, err := DecodeSession(loadData(t, "sdp_session_ex_full", testNL), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Reset()
+		d = NewDecoder(other)
+		if err = d.Decode(m); err != nil {
+			t.Fatal(err)
+		}
+		if name != expected.Name || mid != "data" {
+			t.Errorf("strings changed after reuse of message: %q, %q", name, mid)
+		}
+	})
+	t.Run("ZeroCopy", func(t *testing.T) {
+		s, err := DecodeSession(tData, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := new(Message)
+		d := NewDecoderWithOptions(s, DecoderOptions{ZeroCopy: true})
+		if err = d.Decode(m); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(m, expected) {
+			t.Fatal("zero-copy result differs")
+		}
+		corrupt(s)
+		if m.Origin.Username != "x" {
+			t.Errorf("username %q should reference session", m.Origin.Username)
+		}
+	})
+}
+
+func TestDecoder_AllErrors(t *testing.T) {
+	in := `v=0
+o=jdoe 2890844526 bad IN IP4 10.47.16.5
+c=IN IP4 300.0.0.1/127
+t=2873397496 2873404696
+r=7d 1x 0
+m=audio 49170 RTP/AVP 0
+b=AS:fast
+m=video 51372/x RTP/AVP 99
+a=rtpmap:99 h263-1998/90000
+t=0 0
+`
+	s, err := DecodeSession([]byte(in), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewDecoderWithOptions(s, DecoderOptions{AllErrors: true})
+	m := new(Message)
+	err = d.Decode(m)
+	list, ok := err.(ErrorList)
+	if !ok {
+		t.Fatalf("unexpected error %T: %v", err, err)
+	}
+	expected := []FieldError{
+		{Line: 2, Column: 19, Type: TypeOrigin, Section: SectionSession, Code: CodeInvalidNumber},
+		{Line: 3, Column: 10, Type: TypeConnectionData, Section: SectionSession, Code: CodeInvalidIP},
+		{Line: 5, Column: 6, Type: TypeRepeatTimes, Section: SectionTime, Code: CodeInvalidNumber},
+		{Line: 7, Column: 6, Type: TypeBandwidth, Section: SectionMedia, Code: CodeInvalidNumber},
+		{Line: 8, Column: 15, Type: TypeMediaDescription, Section: SectionMedia, Index: 1, Code: CodeInvalidNumber},
+		{Line: 10, Column: 3, Type: TypeTiming, Section: SectionMedia, Index: 1, Code: CodeUnexpectedField},
+		{Type: TypeOrigin, Section: SectionSession, Code: CodeMissingField},
+		{Type: TypeSessionName, Section: SectionSession, Code: CodeMissingField},
+	}
+	if len(list) != len(expected) {
+		t.Fatalf("len(errors) %d != %d: %v", len(list), len(expected), list)
+	}
+	for i, e := range expected {
+		got := *list[i]
+		got.Err = nil
+		if got != e {
+			t.Errorf("[%d] %+v != %+v", i, got, e)
+		}
+	}
+	for _, target := range []error{
+		ErrFailedToDecodeIP, ErrInvalidNumber, ErrUnexpectedField, ErrMissingField,
+	} {
+		if !errors.Is(err, target) {
+			t.Errorf("errors.Is(err, %v) should be true", target)
+		}
+	}
+	if errors.Is(err, ErrInvalidSyntax) {
+		t.Error("errors.Is(err, ErrInvalidSyntax) should be false")
+	}
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr != list[0] {
+		t.Error("errors.As should find first error")
+	}
+	if len(m.Medias) != 2 || m.Medias[1].Attribute("rtpmap") == "" {
+		t.Error("decoding should continue after errors")
+	}
+}
+
+func TestDecoder_FieldError(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		in     string
+		line   int
+		column int
+		target error
+	}{
+		{"IP", "v=0\no=- 1 2 IN IP4 127.0.0.1\ns=-\nc=IN IP4 bad\n", 4, 10, ErrFailedToDecodeIP},
+		{"Order", "v=0\ns=-\no=- 1 2 IN IP4 127.0.0.1\n", 3, 3, ErrUnexpectedField},
+		{"Syntax", "v=0\no=- 1 2 IN IP4 127.0.0.1\ns=-\na=key:\n", 4, 7, ErrInvalidSyntax},
+		{"Space", "v=0\no=- 1  2 IN IP4 127.0.0.1\n", 2, 7, ErrInvalidSyntax},
+		{"Missing", "v=0\ns=-\n", 0, 0, ErrMissingField},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := DecodeSession([]byte(tc.in), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := NewDecoder(s)
+			err = d.Decode(new(Message))
+			if !errors.Is(err, tc.target) {
+				t.Fatalf("errors.Is(%v, %v) should be true", err, tc.target)
+			}
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatal("errors.As failed")
+			}
+			if fieldErr.Line != tc.line || fieldErr.Column != tc.column {
+				t.Errorf("position %d:%d != %d:%d",
+					fieldErr.Line, fieldErr.Column, tc.line, tc.column,
+				)
+			}
+		})
+	}
+}
+
+func TestDecoder_Lenient(t *testing.T) {
+	const (
+		prefix = "v=0\no=- 1 2 IN IP4 127.0.0.1\ns=-\n"
+		timing = "t=0 0\n"
+	)
+	type warning struct {
+		line int
+		code ErrorCode
+	}
+	for _, tc := range []struct {
+		name     string
+		in       string
+		rule     LenientRules
+		warnings []warning
+	}{
+		{
+			name:     "ConnectionAfterBandwidth",
+			in:       prefix + "b=AS:64\nc=IN IP4 127.0.0.1\n" + timing,
+			rule:     LenientConnectionAfterBandwidth,
+			warnings: []warning{{5, CodeUnexpectedField}},
+		},
+		{
+			name:     "ConnectionAfterBandwidthMedia",
+			in:       prefix + timing + "m=audio 1 RTP/AVP 0\nb=AS:64\nc=IN IP4 127.0.0.1\n",
+			rule:     LenientConnectionAfterBandwidth,
+			warnings: []warning{{7, CodeUnexpectedField}},
+		},
+		{
+			name:     "EncryptionBeforeConnection",
+			in:       prefix + timing + "m=audio 1 RTP/AVP 0\nk=prompt\nc=IN IP4 127.0.0.1\n",
+			rule:     LenientEncryptionBeforeConnection,
+			warnings: []warning{{7, CodeUnexpectedField}},
+		},
+		{
+			name:     "MissingTiming",
+			in:       prefix + "m=audio 1 RTP/AVP 0\n",
+			rule:     LenientMissingTiming,
+			warnings: []warning{{0, CodeMissingField}},
+		},
+		{
+			name:     "TimeZonesBeforeTiming",
+			in:       prefix + "z=2882844526 -1h\n" + timing,
+			rule:     LenientTimeZonesBeforeTiming,
+			warnings: []warning{{4, CodeUnexpectedField}},
+		},
+		{
+			name: "ExtraSpaces",
+			in: prefix + "c=IN  IP4 127.0.0.1 \nt=0   0\n" +
+				"m=audio  1 RTP/AVP 0\n",
+			rule: LenientExtraSpaces,
+			warnings: []warning{
+				{4, CodeInvalidSyntax},
+				{5, CodeInvalidSyntax},
+				{6, CodeInvalidSyntax},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := DecodeSession([]byte(tc.in), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Run("Strict", func(t *testing.T) {
+				d := NewDecoderWithOptions(s, DecoderOptions{
+					Strict:  true,
+					Lenient: LenientAll &^ tc.rule,
+				})
+				if err := d.Decode(new(Message)); err == nil {
+					t.Error("should fail")
+				}
+			})
+			t.Run("Lenient", func(t *testing.T) {
+				d := NewDecoderWithOptions(s, DecoderOptions{
+					Strict:  true,
+					Lenient: tc.rule,
+				})
+				m := new(Message)
+				if err := d.Decode(m); err != nil {
+					t.Fatal(err)
+				}
+				if m.Origin.Address != "127.0.0.1" {
+					t.Error("origin not decoded")
+				}
+				warnings := d.Warnings()
+				if len(warnings) != len(tc.warnings) {
+					t.Fatalf("unexpected warnings: %v", warnings)
+				}
+				for i, w := range tc.warnings {
+					if warnings[i].Line != w.line || warnings[i].Code != w.code {
+						t.Errorf("warning %d: %v", i, warnings[i])
+					}
+				}
+			})
+		})
+	}
+	t.Run("Default", func(t *testing.T) {
+		s, err := DecodeSession([]byte(prefix+"b=AS:64\nc=IN IP4 127.0.0.1\n"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := NewDecoder(s)
+		m := new(Message)
+		if err = d.Decode(m); err != nil {
+			t.Fatal(err)
+		}
+		if len(d.Warnings()) != 2 {
+			t.Errorf("unexpected warnings: %v", d.Warnings())
+		}
+		if m.Connection.IP == nil || m.Bandwidths.Value(BandwidthApplicationSpecific) != 64 {
+			t.Error("fields not decoded")
+		}
+	})
+	t.Run("AllErrors", func(t *testing.T) {
+		s, err := DecodeSession([]byte(prefix+timing+"b=AS:64\nc=IN IP4 127.0.0.1\n"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := NewDecoderWithOptions(s, DecoderOptions{Strict: true, AllErrors: true})
+		m := new(Message)
+		err = d.Decode(m)
+		if !errors.Is(err, ErrUnexpectedField) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if m.Connection.IP != nil {
+			t.Error("misplaced field should be skipped")
+		}
+	})
+}
+
+func decodeReusing(s Session, d *Decoder, m *Message, data []byte) (Session, error) {
+	s, err := DecodeSession(data, s.reset())
+	if err != nil {
+		return s, err
+	}
+	m.Reset()
+	d.Reset(s)
+	return s, d.Decode(m)
+}
+
+func TestDecoder_Reset(t *testing.T) {
+	webrtc := loadData(t, "spd_session_ex_webrtc1", testCRNL)
+	full := loadData(t, "sdp_session_ex_full", testCRNL)
+	var (
+		s   Session
+		d   = NewDecoderWithOptions(nil, DecoderOptions{ZeroCopy: true})
+		m   = new(Message)
+		err error
+	)
+	for _, data := range [][]byte{webrtc, full, webrtc, full} {
+		if s, err = decodeReusing(s, &d, m, data); err != nil {
+			t.Fatal(err)
+		}
+		expected, err := Decode(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(m.Append(nil).AppendTo(nil), expected.Append(nil).AppendTo(nil)) {
+			t.Error("reused message differs")
+		}
+	}
+	t.Run("ZeroAllocs", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(10, func() {
+			if s, err = decodeReusing(s, &d, m, webrtc); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs > 0 {
+			t.Errorf("allocs %v > 0", allocs)
+		}
+	})
+}
+
+func TestDecodeIP(t *testing.T) {
+	for _, tc := range []string{
+		"0.0.0.0", "127.0.0.1", "255.255.255.255", "224.2.1.1",
+		"256.0.0.1", "1.2.3", "1.2.3.4.5", "01.2.3.4", "1..2.3", "1.2.3.4 ",
+		"1.2.3.-4", "1.2.3.1234", "::1", "FF15::101", "2001:db8::2", "", "x",
+	} {
+		ip, err := decodeIP(nil, []byte(tc))
+		expected := net.ParseIP(tc)
+		if (err == nil) != (expected != nil) {
+			t.Errorf("%q: unexpected error %v", tc, err)
+			continue
+		}
+		if !bytes.Equal(ip, expected) {
+			t.Errorf("%q: %v != %v", tc, ip, expected)
+		}
+	}
+	t.Run("Reuse", func(t *testing.T) {
+		dst := make(net.IP, 0, net.IPv6len)
+		ip, err := decodeIP(dst, []byte("10.0.0.1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if &ip[0] != &dst[:1][0] {
+			t.Error("memory of blank IP should be reused")
+		}
+		dst = net.IPv4(127, 0, 0, 1)
+		if _, err = decodeIP(dst, []byte("10.0.0.1")); err != nil {
+			t.Fatal(err)
+		}
+		if !dst.Equal(net.IPv4(127, 0, 0, 1)) {
+			t.Error("non-blank IP should not be modified")
+		}
+	})
+}
+
+func BenchmarkDecoder_WebRTC(b *testing.B) {
+	data := loadData(b, "spd_session_ex_webrtc1", testCRNL)
+	var (
+		s   Session
+		d   = NewDecoderWithOptions(nil, DecoderOptions{ZeroCopy: true})
+		m   = new(Message)
+		err error
+	)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		if s, err = decodeReusing(s, &d, m, data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
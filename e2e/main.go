@@ -4,7 +4,6 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
@@ -30,17 +29,8 @@ func main() {
 	fs := http.FileServer(http.Dir("static"))
 	http.HandleFunc("/sdp", func(writer http.ResponseWriter, request *http.Request) {
 		log.Println("http:", request.Method, request.URL.Path, request.RemoteAddr)
-		buf, err := ioutil.ReadAll(request.Body)
-		if err != nil {
-			log.Fatalln("failed to read:", err)
-		}
-		s, err := sdp.DecodeSession(buf, nil)
-		if err != nil {
-			log.Fatalln("failed to decode session:", err)
-		}
-		decoder := sdp.NewDecoder(s)
 		message := new(sdp.Message)
-		if err := decoder.Decode(message); err != nil {
+		if err := sdp.NewReader(request.Body).Decode(message); err != nil {
 			log.Fatalln("failed to decode message:", err)
 		}
 		log.Println("decoded address:", message.Origin.Address)
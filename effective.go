@@ -0,0 +1,195 @@
+package sdp
+
+import "sync"
+
+// AttributeLevel describes where attribute can be used and whether
+// session-level value applies to media sections.
+type AttributeLevel int
+
+// Possible attribute levels.
+const (
+	// LevelInherited attribute at session level applies to every media
+	// section that has no attribute with same key.
+	LevelInherited AttributeLevel = iota
+	// LevelSessionOnly attribute describes whole session and has no
+	// meaning in media section.
+	LevelSessionOnly
+	// LevelMediaOnly attribute is not applied to media sections from
+	// session level.
+	LevelMediaOnly
+)
+
+var levelToStr = map[AttributeLevel]string{
+	LevelInherited:   "inherited",
+	LevelSessionOnly: "session-only",
+	LevelMediaOnly:   "media-only",
+}
+
+func (l AttributeLevel) String() string {
+	if s, ok := levelToStr[l]; ok {
+		return s
+	}
+	return "unknown"
+}
+
+// Direction is media direction attribute, like "a=recvonly".
+type Direction string
+
+// Possible directions, DirectionSendRecv is default one.
+const (
+	DirectionSendRecv Direction = "sendrecv"
+	DirectionSendOnly Direction = "sendonly"
+	DirectionRecvOnly Direction = "recvonly"
+	DirectionInactive Direction = "inactive"
+)
+
+func isDirection(key string) bool {
+	switch Direction(key) {
+	case DirectionSendRecv, DirectionSendOnly, DirectionRecvOnly, DirectionInactive:
+		return true
+	default:
+		return false
+	}
+}
+
+// Direction returns first direction attribute, if any.
+func (a Attributes) Direction() (Direction, bool) {
+	for _, v := range a {
+		if isDirection(v.Key) {
+			return Direction(v.Key), true
+		}
+	}
+	return "", false
+}
+
+var (
+	attributeLevelsMux sync.RWMutex
+	attributeLevels    = map[string]AttributeLevel{
+		// RFC 8866 Section 6.
+		"cat":       LevelSessionOnly,
+		"keywds":    LevelSessionOnly,
+		"tool":      LevelSessionOnly,
+		"type":      LevelSessionOnly,
+		"charset":   LevelInherited,
+		"sdplang":   LevelInherited,
+		"lang":      LevelInherited,
+		"ptime":     LevelInherited,
+		"maxptime":  LevelInherited,
+		"quality":   LevelInherited,
+		"sendrecv":  LevelInherited,
+		"sendonly":  LevelInherited,
+		"recvonly":  LevelInherited,
+		"inactive":  LevelInherited,
+		"rtpmap":    LevelMediaOnly,
+		"fmtp":      LevelMediaOnly,
+		"orient":    LevelMediaOnly,
+		"framerate": LevelMediaOnly,
+		// RFC 5888, RFC 8839, RFC 8122, RFC 4145, RFC 8285.
+		"group":       LevelSessionOnly,
+		"mid":         LevelMediaOnly,
+		"ice-lite":    LevelSessionOnly,
+		"ice-options": LevelSessionOnly,
+		"ice-ufrag":   LevelInherited,
+		"ice-pwd":     LevelInherited,
+		"candidate":   LevelMediaOnly,
+		"fingerprint": LevelInherited,
+		"setup":       LevelInherited,
+		"extmap":      LevelInherited,
+		// RFC 3605, RFC 5761, RFC 4585, RFC 5576.
+		"rtcp":       LevelMediaOnly,
+		"rtcp-mux":   LevelMediaOnly,
+		"rtcp-fb":    LevelMediaOnly,
+		"ssrc":       LevelMediaOnly,
+		"ssrc-group": LevelMediaOnly,
+	}
+)
+
+// RegisterAttributeLevel sets level of attribute key that is used by
+// Message.Effective, replacing previous one.
+func RegisterAttributeLevel(key string, l AttributeLevel) {
+	attributeLevelsMux.Lock()
+	attributeLevels[key] = l
+	attributeLevelsMux.Unlock()
+}
+
+// LookupAttributeLevel returns level of attribute key. Attributes that
+// are not in table are not inherited, like LevelMediaOnly.
+func LookupAttributeLevel(key string) (AttributeLevel, bool) {
+	attributeLevelsMux.RLock()
+	l, ok := attributeLevels[key]
+	attributeLevelsMux.RUnlock()
+	if !ok {
+		return LevelMediaOnly, false
+	}
+	return l, true
+}
+
+// EffectiveMedia is media section with session-level values applied.
+type EffectiveMedia struct {
+	Title       string
+	Description MediaDescription
+	Connections []ConnectionData
+	Bandwidths  Bandwidths
+	Encryption  Encryption
+	Direction   Direction
+	Attributes  Attributes
+}
+
+// Effective returns m.Medias[i] with session-level connection data,
+// bandwidths, encryption and attributes applied as described by
+// RFC 8866 Section 5, where values of media section override session
+// ones. Attributes are merged using levels from LookupAttributeLevel:
+// inherited session attributes come first, followed by media ones,
+// and session-only attributes of media section are dropped.
+// Returns false if there is no i-th media.
+//
+// Values of returned EffectiveMedia can share memory with m.
+func (m *Message) Effective(i int) (EffectiveMedia, bool) {
+	if i < 0 || i >= len(m.Medias) {
+		return EffectiveMedia{}, false
+	}
+	media := &m.Medias[i]
+	e := EffectiveMedia{
+		Title:       media.Title,
+		Description: media.Description,
+		Connections: media.Connections,
+		Bandwidths:  media.Bandwidths,
+		Encryption:  media.Encryption,
+		Direction:   DirectionSendRecv,
+	}
+	if len(e.Connections) == 0 && !m.Connection.Blank() {
+		e.Connections = []ConnectionData{m.Connection}
+	}
+	if len(e.Bandwidths) == 0 {
+		e.Bandwidths = m.Bandwidths
+	}
+	if e.Encryption.Blank() {
+		e.Encryption = m.Encryption
+	}
+	if d, ok := media.Attributes.Direction(); ok {
+		e.Direction = d
+	} else if d, ok = m.Attributes.Direction(); ok {
+		e.Direction = d
+	}
+	_, mediaDirection := media.Attributes.Direction()
+	for _, a := range m.Attributes {
+		if l, _ := LookupAttributeLevel(a.Key); l != LevelInherited {
+			continue
+		}
+		if isDirection(a.Key) && mediaDirection {
+			continue
+		}
+		if media.Attributes.Flag(a.Key) {
+			// Overridden by media section.
+			continue
+		}
+		e.Attributes = append(e.Attributes, a)
+	}
+	for _, a := range media.Attributes {
+		if l, _ := LookupAttributeLevel(a.Key); l == LevelSessionOnly {
+			continue
+		}
+		e.Attributes = append(e.Attributes, a)
+	}
+	return e, true
+}
@@ -0,0 +1,139 @@
+package sdp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMessage_Effective(t *testing.T) {
+	m, err := Decode(loadData(t, "sdp_session_ex_full", testNL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	audio, ok := m.Effective(0)
+	if !ok {
+		t.Fatal("audio not found")
+	}
+	if len(audio.Connections) != 1 || !audio.Connections[0].Equal(m.Connection) {
+		t.Error("connection should be inherited", audio.Connections)
+	}
+	if audio.Encryption != m.Encryption {
+		t.Error("encryption should be inherited", audio.Encryption)
+	}
+	if audio.Bandwidths.Value(BandwidthConferenceTotal) != 154798 {
+		t.Error("bandwidths should be inherited", audio.Bandwidths)
+	}
+	if audio.Direction != DirectionRecvOnly {
+		t.Error("direction should be inherited", audio.Direction)
+	}
+	if audio.Title != "Some audio" {
+		t.Error("unexpected title", audio.Title)
+	}
+	video, ok := m.Effective(1)
+	if !ok {
+		t.Fatal("video not found")
+	}
+	if video.Encryption.Method != EncryptionPrompt {
+		t.Error("encryption should be overridden", video.Encryption)
+	}
+	expected := Bandwidths{{Type: BandwidthApplicationSpecific, Value: 66781}}
+	if !video.Bandwidths.Equal(expected) {
+		t.Error(video.Bandwidths, "!=", expected)
+	}
+	if video.Attributes.Value("rtpmap") != "99 h263-1998/90000" || !video.Attributes.Flag("recvonly") {
+		t.Error("unexpected attributes", video.Attributes)
+	}
+	for _, i := range []int{-1, 2} {
+		if _, ok := m.Effective(i); ok {
+			t.Errorf("media %d should not be found", i)
+		}
+	}
+}
+
+func TestMessage_Effective_Attributes(t *testing.T) {
+	m := &Message{
+		Attributes: Attributes{
+			{Key: "tool", Value: "test"},
+			{Key: "sendonly"},
+			{Key: "ptime", Value: "20"},
+			{Key: "charset", Value: "UTF-8"},
+			{Key: "rtpmap", Value: "0 PCMU/8000"},
+			{Key: "x-unknown", Value: "1"},
+		},
+		Medias: Medias{
+			{
+				Connections: []ConnectionData{{IP: []byte{10, 0, 0, 1}}},
+				Attributes: Attributes{
+					{Key: "inactive"},
+					{Key: "ptime", Value: "30"},
+					{Key: "ptime", Value: "40"},
+					{Key: "cat", Value: "misplaced"},
+					{Key: "x-media"},
+				},
+			},
+			{},
+		},
+	}
+	first, _ := m.Effective(0)
+	expected := Attributes{
+		{Key: "charset", Value: "UTF-8"},
+		{Key: "inactive"},
+		{Key: "ptime", Value: "30"},
+		{Key: "ptime", Value: "40"},
+		{Key: "x-media"},
+	}
+	if !reflect.DeepEqual(first.Attributes, expected) {
+		t.Error(first.Attributes, "!=", expected)
+	}
+	if first.Direction != DirectionInactive {
+		t.Error("unexpected direction", first.Direction)
+	}
+	if len(first.Connections) != 1 || first.Connections[0].IP.String() != "10.0.0.1" {
+		t.Error("unexpected connections", first.Connections)
+	}
+	second, _ := m.Effective(1)
+	expected = Attributes{
+		{Key: "sendonly"},
+		{Key: "ptime", Value: "20"},
+		{Key: "charset", Value: "UTF-8"},
+	}
+	if !reflect.DeepEqual(second.Attributes, expected) {
+		t.Error(second.Attributes, "!=", expected)
+	}
+	if second.Direction != DirectionSendOnly || len(second.Connections) != 0 {
+		t.Error("unexpected", second)
+	}
+	t.Run("Default", func(t *testing.T) {
+		m := &Message{Medias: Medias{{}}}
+		if e, _ := m.Effective(0); e.Direction != DirectionSendRecv {
+			t.Error("unexpected direction", e.Direction)
+		}
+	})
+	t.Run("Register", func(t *testing.T) {
+		RegisterAttributeLevel("x-unknown", LevelInherited)
+		defer func() {
+			attributeLevelsMux.Lock()
+			delete(attributeLevels, "x-unknown")
+			attributeLevelsMux.Unlock()
+		}()
+		if e, _ := m.Effective(1); e.Attributes.Value("x-unknown") != "1" {
+			t.Error("should be inherited")
+		}
+	})
+}
+
+func TestAttributeLevel_String(t *testing.T) {
+	for l, s := range map[AttributeLevel]string{
+		LevelInherited:     "inherited",
+		LevelSessionOnly:   "session-only",
+		LevelMediaOnly:     "media-only",
+		AttributeLevel(42): "unknown",
+	} {
+		if l.String() != s {
+			t.Error(l.String(), "!=", s)
+		}
+	}
+	if l, ok := LookupAttributeLevel("x-not-registered"); ok || l != LevelMediaOnly {
+		t.Error("unexpected level", l, ok)
+	}
+}
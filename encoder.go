@@ -1,5 +1,11 @@
 package sdp
 
+import (
+	"io"
+
+	"github.com/pkg/errors"
+)
+
 func (s Session) appendAttributes(attrs Attributes) Session {
 	for _, v := range attrs {
 		if v.Value == blank {
@@ -13,8 +19,21 @@ func (s Session) appendAttributes(attrs Attributes) Session {
 
 // Append encodes message to Session and returns result.
 //
+// If message was decoded in lossless mode and was not modified since,
+// original lines are appended verbatim.
+//
 // See RFC 4566 Section 5.
 func (m *Message) Append(s Session) Session {
+	s = m.Raw.appendTo(s, m.appendSessionSection)
+	for i := range m.Medias {
+		s = m.Medias[i].Raw.appendTo(s, m.Medias[i].appendSection)
+	}
+	return s
+}
+
+// appendSessionSection appends all fields of message that precede
+// media descriptions.
+func (m *Message) appendSessionSection(s Session) Session {
 	s = s.AddVersion(m.Version)
 	s = s.AddOrigin(m.Origin)
 	s = s.AddSessionName(m.Name)
@@ -24,21 +43,21 @@ func (m *Message) Append(s Session) Session {
 	if len(m.URI) > 0 {
 		s = s.AddURI(m.URI)
 	}
-	if len(m.Email) > 0 {
-		s = s.AddEmail(m.Email)
+	for _, a := range m.Emails {
+		s = s.AddEmailAddress(a)
 	}
-	if len(m.Phone) > 0 {
-		s = s.AddPhone(m.Phone)
+	for _, a := range m.Phones {
+		s = s.AddPhoneAddress(a)
 	}
 	if !m.Connection.Blank() {
 		s = s.AddConnectionData(m.Connection)
 	}
-	for t, v := range m.Bandwidths {
-		s = s.AddBandwidth(t, v)
-	}
+	s = s.appendBandwidths(m.Bandwidths)
 	// One or more time descriptions ("t=" and "r=" lines)
-	for _, t := range m.Timing {
-		s = s.AddTiming(t.Start, t.End)
+	for i, t := range m.Timing {
+		if i == 0 || !t.sameRepeatedTime(m.Timing[i-1]) {
+			s = s.AddTiming(t.Start, t.End)
+		}
 		if len(t.Offsets) > 0 {
 			s = s.AddRepeatTimesCompact(t.Repeat, t.Active, t.Offsets...)
 		}
@@ -49,23 +68,199 @@ func (m *Message) Append(s Session) Session {
 	if !m.Encryption.Blank() {
 		s = s.AddEncryption(m.Encryption)
 	}
-	s = s.appendAttributes(m.Attributes)
+	return s.appendAttributes(m.Attributes)
+}
 
-	for i := range m.Medias {
-		s = s.AddMediaDescription(m.Medias[i].Description)
-		if len(m.Medias[i].Title) > 0 {
-			s = s.AddSessionInfo(m.Medias[i].Title)
+// appendSection appends media description and all media fields.
+func (m *Media) appendSection(s Session) Session {
+	s = s.AddMediaDescription(m.Description)
+	if len(m.Title) > 0 {
+		s = s.AddSessionInfo(m.Title)
+	}
+	for _, c := range m.Connections {
+		s = s.AddConnectionData(c)
+	}
+	s = s.appendBandwidths(m.Bandwidths)
+	if !m.Encryption.Blank() {
+		s = s.AddEncryption(m.Encryption)
+	}
+	return s.appendAttributes(m.Attributes)
+}
+
+// appendBandwidths appends bandwidth fields in order of list.
+func (s Session) appendBandwidths(b Bandwidths) Session {
+	for _, v := range b {
+		s = s.AddBandwidth(v.Type, v.Value)
+	}
+	return s
+}
+
+// appendTo appends section to s using encode. Original lines are
+// appended instead if section is not modified since decoding, and
+// lines of unknown types are preserved otherwise.
+func (r *RawSection) appendTo(s Session, encode func(Session) Session) Session {
+	start := len(s)
+	s = encode(s)
+	if len(r.Lines) == 0 {
+		return s
+	}
+	if s[start:].Equal(r.canonical) {
+		// Not modified, replacing with original lines.
+		s = s[:start]
+		for _, l := range r.Lines {
+			s = s.append(l.Type, l.Value)
 		}
-		if !m.Medias[i].Connection.Blank() {
-			s = s.AddConnectionData(m.Medias[i].Connection)
+		return s
+	}
+	// Modified, so inserting unknown lines after the same count of
+	// known lines that preceded them in original section.
+	known := s[start:].clone()
+	s = s[:start]
+	var appended, seen int
+	for _, l := range r.Lines {
+		if isKnown(l.Type) {
+			seen++
+			continue
 		}
-		for t, v := range m.Medias[i].Bandwidths {
-			s = s.AddBandwidth(t, v)
+		for ; appended < seen && appended < len(known); appended++ {
+			s = s.append(known[appended].Type, known[appended].Value)
 		}
+		s = s.append(l.Type, l.Value)
+	}
+	for ; appended < len(known); appended++ {
+		s = s.append(known[appended].Type, known[appended].Value)
+	}
+	return s
+}
+
+// EncodeOptions controls textual representation of encoded SDP.
+// Zero value is valid and results in CRLF line delimiters with
+// trailing delimiter after the last line.
+type EncodeOptions struct {
+	// LF sets "\n" as line delimiter instead of "\r\n".
+	LF bool
+	// NoTrailingNewLine omits line delimiter after the last line.
+	NoTrailingNewLine bool
+	// Spec is specification to follow, RFC4566 by default. Messages
+	// with encryption keys are not encoded for RFC8866, because "k="
+	// field is obsolete, unless ForceEncryption is set.
+	//
+	// Time zones field is encoded after the last time description,
+	// which is valid for RFC8866 only if it has repeat times.
+	Spec Spec
+	// ForceEncryption enables encoding of obsolete "k=" field.
+	ForceEncryption bool
+	// Validate enables validation of message before encoding, so
+	// message with missing or malformed fields is not encoded.
+	// See Message.Validate.
+	Validate bool
+}
+
+// check returns error if m can't be encoded with options.
+func (o EncodeOptions) check(m *Message) error {
+	if o.Validate {
+		if err := m.Validate(); err != nil {
+			return err
+		}
+	}
+	if o.Spec != RFC8866 {
+		return nil
+	}
+	if len(m.TZAdjustments) > 0 && (len(m.Timing) == 0 || len(m.Timing[len(m.Timing)-1].Offsets) == 0) {
+		// RFC 8866: repeat-description = 1*repeat-field [zone-field]
+		return errors.Wrap(ErrUnexpectedField, "time zones without repeat times")
+	}
+	if o.ForceEncryption {
+		return nil
+	}
+	if !m.Encryption.Blank() {
+		return errors.Wrap(ErrObsoleteField, "session encryption key")
+	}
+	for i := range m.Medias {
 		if !m.Medias[i].Encryption.Blank() {
-			s = s.AddEncryption(m.Medias[i].Encryption)
+			return errors.Wrapf(ErrObsoleteField, "media %d encryption key", i)
 		}
-		s = s.appendAttributes(m.Medias[i].Attributes)
 	}
-	return s
+	return nil
+}
+
+func (o EncodeOptions) appendNewLine(b []byte) []byte {
+	if o.LF {
+		return append(b, newLine)
+	}
+	return appendCLRF(b)
+}
+
+// AppendSession appends all session lines to b and returns b.
+func (o EncodeOptions) AppendSession(b []byte, s Session) []byte {
+	for i, l := range s {
+		b = l.AppendTo(b)
+		if o.NoTrailingNewLine && i == len(s)-1 {
+			break
+		}
+		b = o.appendNewLine(b)
+	}
+	return b
+}
+
+// Marshal encodes m to new byte slice.
+//
+// ErrObsoleteField is returned if m has fields that are obsolete in
+// o.Spec, and *ValidationError if validation is enabled and fails.
+func (o EncodeOptions) Marshal(m *Message) ([]byte, error) {
+	if err := o.check(m); err != nil {
+		return nil, errors.Wrap(err, "failed to encode")
+	}
+	return o.AppendSession(nil, m.Append(nil)), nil
+}
+
+// Write encodes m and writes result to w, returning number of bytes
+// written and error if any.
+func (o EncodeOptions) Write(w io.Writer, m *Message) (int64, error) {
+	b, err := o.Marshal(m)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// WriteTo encodes message and writes it to w. Implements io.WriterTo.
+func (m *Message) WriteTo(w io.Writer) (int64, error) {
+	return EncodeOptions{}.Write(w, m)
+}
+
+// MarshalText encodes message. Implements encoding.TextMarshaler for
+// both Message and *Message.
+func (m Message) MarshalText() ([]byte, error) {
+	return EncodeOptions{}.Marshal(&m)
+}
+
+// UnmarshalText decodes b to message, replacing all its fields.
+// Implements encoding.TextUnmarshaler.
+func (m *Message) UnmarshalText(b []byte) error {
+	s, err := DecodeSession(b, nil)
+	if err != nil {
+		return err
+	}
+	*m = Message{}
+	d := NewDecoder(s)
+	return d.Decode(m)
+}
+
+// MarshalText encodes session. Implements encoding.TextMarshaler.
+func (s Session) MarshalText() ([]byte, error) {
+	return s.AppendTo(nil), nil
+}
+
+// UnmarshalText decodes b to session, replacing all its lines.
+// Implements encoding.TextUnmarshaler.
+func (s *Session) UnmarshalText(b []byte) error {
+	// Not reusing lines, because they can be referenced by Message.
+	v, err := DecodeSession(b, nil)
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
 }
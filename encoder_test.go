@@ -1,7 +1,10 @@
 package sdp
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"strings"
 	"testing"
@@ -18,13 +21,15 @@ func TestMessage_Append(t *testing.T) {
 			Protocol: "RTP/AVP",
 		},
 		Bandwidths: Bandwidths{
-			BandwidthApplicationSpecificTransportIndependent: 96000,
+			{Type: BandwidthApplicationSpecificTransportIndependent, Value: 96000},
 		},
-		Connection: ConnectionData{
-			NetworkType: "IN",
-			AddressType: "IP4",
-			IP:          net.ParseIP("224.2.1.1"),
-			TTL:         127,
+		Connections: []ConnectionData{
+			{
+				NetworkType: "IN",
+				AddressType: "IP4",
+				IP:          net.ParseIP("224.2.1.1"),
+				TTL:         127,
+			},
 		},
 	}
 	video := Media{
@@ -36,7 +41,7 @@ func TestMessage_Append(t *testing.T) {
 			Protocol: "RTP/AVP",
 		},
 		Bandwidths: Bandwidths{
-			BandwidthApplicationSpecific: 66781,
+			{Type: BandwidthApplicationSpecific, Value: 66781},
 		},
 		Encryption: Encryption{
 			Method: "prompt",
@@ -51,17 +56,17 @@ func TestMessage_Append(t *testing.T) {
 			SessionVersion: 2890842807,
 			Address:        "10.47.16.5",
 		},
-		Name:  "SDP Seminar",
-		Info:  "A Seminar on the session description protocol",
-		URI:   "http://www.example.com/seminars/sdp.pdf",
-		Email: "j.doe@example.com (Jane Doe)",
-		Phone: "12345",
+		Name:   "SDP Seminar",
+		Info:   "A Seminar on the session description protocol",
+		URI:    "http://www.example.com/seminars/sdp.pdf",
+		Emails: []Address{{Address: "j.doe@example.com", Name: "Jane Doe"}},
+		Phones: []Address{{Address: "12345"}},
 		Connection: ConnectionData{
 			IP:  net.ParseIP("224.2.17.12"),
 			TTL: 127,
 		},
 		Bandwidths: Bandwidths{
-			BandwidthConferenceTotal: 154798,
+			{Type: BandwidthConferenceTotal, Value: 154798},
 		},
 		Timing: []Timing{
 			{
@@ -150,7 +155,7 @@ func BenchmarkEncode(b *testing.B) {
 			Protocol: "RTP/AVP",
 		},
 		Bandwidths: Bandwidths{
-			BandwidthApplicationSpecific: 66781,
+			{Type: BandwidthApplicationSpecific, Value: 66781},
 		},
 		Encryption: Encryption{
 			Method: "prompt",
@@ -165,17 +170,17 @@ func BenchmarkEncode(b *testing.B) {
 			SessionVersion: 2890842807,
 			Address:        "10.47.16.5",
 		},
-		Name:  "SDP Seminar",
-		Info:  "A Seminar on the session description protocol",
-		URI:   "http://www.example.com/seminars/sdp.pdf",
-		Email: "j.doe@example.com (Jane Doe)",
-		Phone: "12345",
+		Name:   "SDP Seminar",
+		Info:   "A Seminar on the session description protocol",
+		URI:    "http://www.example.com/seminars/sdp.pdf",
+		Emails: []Address{{Address: "j.doe@example.com", Name: "Jane Doe"}},
+		Phones: []Address{{Address: "12345"}},
 		Connection: ConnectionData{
 			IP:  net.ParseIP("224.2.17.12"),
 			TTL: 127,
 		},
 		Bandwidths: Bandwidths{
-			BandwidthConferenceTotal: 154798,
+			{Type: BandwidthConferenceTotal, Value: 154798},
 		},
 		Timing: []Timing{
 			{
@@ -205,3 +210,135 @@ func BenchmarkEncode(b *testing.B) {
 		buf = buf[:0]
 	}
 }
+
+func TestEncodeOptions_AppendSession(t *testing.T) {
+	s := new(Session).AddVersion(0).AddSessionName("-")
+	for _, tc := range []struct {
+		name string
+		opts EncodeOptions
+		out  string
+	}{
+		{"Default", EncodeOptions{}, "v=0\r\ns=-\r\n"},
+		{"LF", EncodeOptions{LF: true}, "v=0\ns=-\n"},
+		{"NoTrailing", EncodeOptions{NoTrailingNewLine: true}, "v=0\r\ns=-"},
+		{"LFNoTrailing", EncodeOptions{LF: true, NoTrailingNewLine: true}, "v=0\ns=-"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := string(tc.opts.AppendSession(nil, s)); v != tc.out {
+				t.Errorf("%q != %q", v, tc.out)
+			}
+		})
+	}
+	t.Run("Blank", func(t *testing.T) {
+		if v := (EncodeOptions{NoTrailingNewLine: true}).AppendSession(nil, nil); len(v) != 0 {
+			t.Errorf("unexpected %q", v)
+		}
+	})
+}
+
+func TestMessage_WriteTo(t *testing.T) {
+	data := loadData(t, "sdp_session_ex1", testCRNL)
+	m, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	n, err := m.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("n %d != %d", n, buf.Len())
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Errorf("%q != %q", buf, data)
+	}
+	buf.Reset()
+	if _, err = (EncodeOptions{LF: true}).Write(buf, m); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), loadData(t, "sdp_session_ex1", testNL)) {
+		t.Errorf("unexpected %q", buf)
+	}
+}
+
+func TestMessage_MarshalText(t *testing.T) {
+	data := loadData(t, "sdp_session_ex1", testCRNL)
+	type wrapper struct {
+		Message *Message `json:"sdp"`
+		Session Session  `json:"session"`
+	}
+	var in wrapper
+	in.Message = new(Message)
+	if err := in.Message.UnmarshalText(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := in.Session.UnmarshalText(data); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out wrapper
+	if err = json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Message.Name != "SDP Seminar" || len(out.Message.Medias) != 2 {
+		t.Errorf("unexpected message: %+v", out.Message)
+	}
+	if !out.Session.Equal(in.Session) {
+		t.Error("sessions are not equal")
+	}
+	t.Run("Replace", func(t *testing.T) {
+		m := &Message{Name: "old"}
+		m.AddFlag("sendonly")
+		if err := m.UnmarshalText(data); err != nil {
+			t.Fatal(err)
+		}
+		if m.Flag("sendonly") {
+			t.Error("attributes should be replaced")
+		}
+	})
+	t.Run("Value", func(t *testing.T) {
+		b, err := json.Marshal(struct {
+			Message Message `json:"sdp"`
+		}{Message: *in.Message})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out struct {
+			Message Message `json:"sdp"`
+		}
+		if err = json.Unmarshal(b, &out); err != nil {
+			t.Fatal(err)
+		}
+		if !out.Message.Equal(in.Message) {
+			t.Errorf("%+v != %+v", out.Message, in.Message)
+		}
+	})
+	t.Run("Errors", func(t *testing.T) {
+		if err := new(Message).UnmarshalText([]byte("v")); err == nil {
+			t.Error("should fail")
+		}
+		if err := new(Message).UnmarshalText([]byte("v=0")); err == nil {
+			t.Error("should fail")
+		}
+		if err := new(Session).UnmarshalText([]byte("v")); err == nil {
+			t.Error("should fail")
+		}
+	})
+}
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+func TestEncodeOptions_Write(t *testing.T) {
+	m := &Message{Name: "-"}
+	if _, err := (EncodeOptions{}).Write(failWriter{}, m); err != io.ErrClosedPipe {
+		t.Errorf("unexpected error %v", err)
+	}
+}
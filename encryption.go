@@ -0,0 +1,90 @@
+package sdp
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Encryption methods from RFC 4566 Section 5.12.
+const (
+	EncryptionClear  = "clear"  // key is untransformed
+	EncryptionBase64 = "base64" // key is base64 encoded
+	EncryptionURI    = "uri"    // key is URI to obtain key from
+	EncryptionPrompt = "prompt" // no key, user should be prompted
+)
+
+// ErrEncryptionMethod means that key can't be obtained with called
+// accessor for Encryption.Method.
+var ErrEncryptionMethod = errors.New("unexpected encryption method")
+
+// redacted replaces key material in String and GoString.
+const redacted = "REDACTED"
+
+// NewBase64Encryption returns Encryption with base64 encoded key.
+func NewBase64Encryption(key []byte) Encryption {
+	return Encryption{
+		Method: EncryptionBase64,
+		Key:    base64.StdEncoding.EncodeToString(key),
+	}
+}
+
+// NewURIEncryption returns Encryption with key that can be obtained
+// from u.
+func NewURIEncryption(u *url.URL) Encryption {
+	return Encryption{Method: EncryptionURI, Key: u.String()}
+}
+
+// Bytes returns key for "clear" and "base64" methods, decoding
+// base64 if needed.
+func (e Encryption) Bytes() ([]byte, error) {
+	switch e.Method {
+	case EncryptionClear:
+		return []byte(e.Key), nil
+	case EncryptionBase64:
+		b, err := base64.StdEncoding.DecodeString(e.Key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode base64 key")
+		}
+		return b, nil
+	default:
+		return nil, errors.Wrapf(ErrEncryptionMethod, "%q", e.Method)
+	}
+}
+
+// URL returns parsed key for "uri" method.
+func (e Encryption) URL() (*url.URL, error) {
+	if e.Method != EncryptionURI {
+		return nil, errors.Wrapf(ErrEncryptionMethod, "%q", e.Method)
+	}
+	u, err := url.Parse(e.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse uri key")
+	}
+	return u, nil
+}
+
+// Prompt reports whether user should be prompted for key.
+func (e Encryption) Prompt() bool {
+	return e.Method == EncryptionPrompt
+}
+
+// String returns "<method>:REDACTED" or "<method>", so keys don't
+// leak to logs.
+func (e Encryption) String() string {
+	if e.Key == "" {
+		return e.Method
+	}
+	return e.Method + ":" + redacted
+}
+
+// GoString implements fmt.GoStringer, redacting key.
+func (e Encryption) GoString() string {
+	key := ""
+	if e.Key != "" {
+		key = redacted
+	}
+	return "sdp.Encryption{Method:" + strconv.Quote(e.Method) + ", Key:" + strconv.Quote(key) + "}"
+}
@@ -0,0 +1,137 @@
+package sdp
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestEncryption_Bytes(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		e        Encryption
+		expected []byte
+		err      error
+	}{
+		{"Clear", Encryption{Method: "clear", Key: "ab8c4df8b8f4as8v8iuy8re"}, []byte("ab8c4df8b8f4as8v8iuy8re"), nil},
+		{"Base64", Encryption{Method: "base64", Key: "AQID"}, []byte{1, 2, 3}, nil},
+		{"URI", Encryption{Method: "uri", Key: "https://example.com/key"}, nil, ErrEncryptionMethod},
+		{"Prompt", Encryption{Method: "prompt"}, nil, ErrEncryptionMethod},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := tc.e.Bytes()
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !bytes.Equal(b, tc.expected) {
+				t.Errorf("%v != %v", b, tc.expected)
+			}
+		})
+	}
+	t.Run("InvalidBase64", func(t *testing.T) {
+		if _, err := (Encryption{Method: "base64", Key: "!"}).Bytes(); err == nil {
+			t.Error("should error")
+		}
+	})
+	t.Run("NewBase64Encryption", func(t *testing.T) {
+		e := NewBase64Encryption([]byte{1, 2, 3})
+		if e != (Encryption{Method: "base64", Key: "AQID"}) {
+			t.Error("unexpected", e.GoString())
+		}
+	})
+}
+
+func TestEncryption_URL(t *testing.T) {
+	e := NewURIEncryption(&url.URL{Scheme: "https", Host: "example.com", Path: "/key"})
+	u, err := e.URL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != "https://example.com/key" {
+		t.Error("unexpected url", u)
+	}
+	if _, err = (Encryption{Method: "uri", Key: ":"}).URL(); err == nil {
+		t.Error("should error")
+	}
+	if _, err = (Encryption{Method: "clear"}).URL(); !errors.Is(err, ErrEncryptionMethod) {
+		t.Error("unexpected error", err)
+	}
+}
+
+func TestEncryption_Prompt(t *testing.T) {
+	if !(Encryption{Method: "prompt"}).Prompt() {
+		t.Error("should be prompt")
+	}
+	if (Encryption{Method: "clear", Key: "prompt"}).Prompt() {
+		t.Error("should not be prompt")
+	}
+}
+
+func TestEncryption_String(t *testing.T) {
+	for _, tc := range []struct {
+		e        Encryption
+		str, gos string
+	}{
+		{Encryption{}, "", `sdp.Encryption{Method:"", Key:""}`},
+		{Encryption{Method: "prompt"}, "prompt", `sdp.Encryption{Method:"prompt", Key:""}`},
+		{Encryption{Method: "clear", Key: "secret"}, "clear:REDACTED", `sdp.Encryption{Method:"clear", Key:"REDACTED"}`},
+	} {
+		if v := tc.e.String(); v != tc.str {
+			t.Errorf("%q != %q", v, tc.str)
+		}
+		if v := tc.e.GoString(); v != tc.gos {
+			t.Errorf("%q != %q", v, tc.gos)
+		}
+	}
+	t.Run("Message", func(t *testing.T) {
+		tData := loadData(t, "sdp_session_ex_full", testNL)
+		s, err := DecodeSession(tData, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := new(Message)
+		d := NewDecoder(s)
+		if err = d.Decode(m); err != nil {
+			t.Fatal(err)
+		}
+		for _, format := range []string{"%v", "%+v", "%s", "%#v"} {
+			if v := fmt.Sprintf(format, m); strings.Contains(v, "ab8c4df8b8f4as8v8iuy8re") {
+				t.Errorf("%s: key is not redacted", format)
+			}
+		}
+	})
+	t.Run("Lossless", func(t *testing.T) {
+		tData := loadData(t, "sdp_session_ex_full", testNL)
+		s, err := DecodeSession(tData, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := new(Message)
+		d := NewDecoderWithOptions(s, DecoderOptions{Lossless: true})
+		if err = d.Decode(m); err != nil {
+			t.Fatal(err)
+		}
+		for _, format := range []string{"%v", "%+v", "%s", "%#v"} {
+			for _, v := range []interface{}{m, m.Raw, m.Raw.Lines, m.Medias[1].Raw} {
+				if out := fmt.Sprintf(format, v); strings.Contains(out, "ab8c4df8b8f4as8v8iuy8re") {
+					t.Errorf("%s: key is not redacted in %T", format, v)
+				}
+			}
+		}
+		if out := fmt.Sprint(m.Raw); !strings.Contains(out, "encryption keys: clear:REDACTED") {
+			t.Errorf("unexpected raw section %s", out)
+		}
+		// Original line is still encoded.
+		b, err := m.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), "k=clear:ab8c4df8b8f4as8v8iuy8re") {
+			t.Error("key should be encoded")
+		}
+	})
+}
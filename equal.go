@@ -0,0 +1,183 @@
+package sdp
+
+// EqualOptions configures semantic comparison of messages. Zero value
+// compares all fields, except Raw, that is not semantic.
+type EqualOptions struct {
+	// IgnoreSessionVersion ignores Origin.SessionVersion, that is
+	// incremented on every modification of session.
+	IgnoreSessionVersion bool
+	// IgnoreAttributeOrder compares attributes of every section as
+	// multisets.
+	IgnoreAttributeOrder bool
+}
+
+// Equal returns true if a and b are semantically equal.
+func (o EqualOptions) Equal(a, b *Message) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	origin := b.Origin
+	if o.IgnoreSessionVersion {
+		origin.SessionVersion = a.Origin.SessionVersion
+	}
+	switch {
+	case a.Version != b.Version,
+		!a.Origin.Equal(origin),
+		a.Name != b.Name,
+		a.Info != b.Info,
+		!equalAddresses(a.Emails, b.Emails),
+		!equalAddresses(a.Phones, b.Phones),
+		a.URI != b.URI,
+		!a.Connection.Equal(b.Connection),
+		!a.Encryption.Equal(b.Encryption),
+		!a.Bandwidths.Equal(b.Bandwidths),
+		!o.equalAttributes(a.Attributes, b.Attributes),
+		!equalTimings(a.Timing, b.Timing),
+		!equalTimeZones(a.TZAdjustments, b.TZAdjustments),
+		len(a.Medias) != len(b.Medias):
+		return false
+	}
+	for i := range a.Medias {
+		if !o.EqualMedia(&a.Medias[i], &b.Medias[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualMedia returns true if a and b are semantically equal.
+func (o EqualOptions) EqualMedia(a, b *Media) bool {
+	return a.Title == b.Title &&
+		a.Description.Equal(b.Description) &&
+		equalConnections(a.Connections, b.Connections) &&
+		a.Encryption.Equal(b.Encryption) &&
+		a.Bandwidths.Equal(b.Bandwidths) &&
+		o.equalAttributes(a.Attributes, b.Attributes)
+}
+
+func (o EqualOptions) equalAttributes(a, b Attributes) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	if !o.IgnoreAttributeOrder {
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+	// Comparing count of every attribute, because there are usually
+	// only few of them.
+	for _, v := range a {
+		if a.count(v) != b.count(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// count returns count of attributes that are equal to v.
+func (a Attributes) count(v Attribute) int {
+	n := 0
+	for i := range a {
+		if a[i] == v {
+			n++
+		}
+	}
+	return n
+}
+
+// Equal returns true if b has same bandwidths in same order. Nil and
+// blank Bandwidths are equal.
+func (b Bandwidths) Equal(other Bandwidths) bool {
+	if len(b) != len(other) {
+		return false
+	}
+	for i := range b {
+		if b[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal returns t == b.
+func (t Timing) Equal(b Timing) bool {
+	if !t.Start.Equal(b.Start) || !t.End.Equal(b.End) {
+		return false
+	}
+	if t.Repeat != b.Repeat || t.Active != b.Active {
+		return false
+	}
+	if len(t.Offsets) != len(b.Offsets) {
+		return false
+	}
+	for i := range t.Offsets {
+		if t.Offsets[i] != b.Offsets[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalConnections(a, b []ConnectionData) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalAddresses compares addresses ignoring form of encoding.
+func equalAddresses(a, b []Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Address != b[i].Address || a[i].Name != b[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+func equalTimings(a, b []Timing) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalTimeZones(a, b []TimeZone) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Start.Equal(b[i].Start) || a[i].Offset != b[i].Offset {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal returns true if m and b are semantically equal.
+// See EqualOptions for configurable comparison.
+func (m *Message) Equal(b *Message) bool {
+	return EqualOptions{}.Equal(m, b)
+}
+
+// Equal returns true if m and b are semantically equal.
+// See EqualOptions.EqualMedia for configurable comparison.
+func (m *Media) Equal(b *Media) bool {
+	return EqualOptions{}.EqualMedia(m, b)
+}
@@ -0,0 +1,148 @@
+package sdp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMessage_Equal(t *testing.T) {
+	a, err := Decode(loadData(t, "sdp_session_ex_full", testCRNL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.AddAttribute("foo", "bar")
+	a.Medias[1].AddAttribute("foo", "bar")
+	for _, tc := range []struct {
+		name    string
+		opts    EqualOptions
+		modify  func(m *Message)
+		equal   bool
+		inverse bool // check that a != b without options
+	}{
+		{
+			name:   "Same",
+			modify: func(m *Message) {},
+			equal:  true,
+		},
+		{
+			name:   "Raw",
+			modify: func(m *Message) { m.Raw = RawSection{} },
+			equal:  true,
+		},
+		{
+			name:   "NilBandwidths",
+			modify: func(m *Message) { m.Medias[0].Bandwidths = Bandwidths{} },
+			equal:  true,
+		},
+		{
+			name:   "SessionVersion",
+			modify: func(m *Message) { m.Origin.SessionVersion++ },
+		},
+		{
+			name:    "IgnoreSessionVersion",
+			opts:    EqualOptions{IgnoreSessionVersion: true},
+			modify:  func(m *Message) { m.Origin.SessionVersion++ },
+			equal:   true,
+			inverse: true,
+		},
+		{
+			name:   "SessionID",
+			opts:   EqualOptions{IgnoreSessionVersion: true},
+			modify: func(m *Message) { m.Origin.SessionID++ },
+		},
+		{
+			name: "AttributeOrder",
+			modify: func(m *Message) {
+				a := m.Attributes
+				a[0], a[1] = a[1], a[0]
+			},
+		},
+		{
+			name: "IgnoreAttributeOrder",
+			opts: EqualOptions{IgnoreAttributeOrder: true},
+			modify: func(m *Message) {
+				a := m.Medias[1].Attributes
+				a[0], a[1] = a[1], a[0]
+			},
+			equal:   true,
+			inverse: true,
+		},
+		{
+			name: "AttributeCount",
+			opts: EqualOptions{IgnoreAttributeOrder: true},
+			modify: func(m *Message) {
+				m.Attributes[0] = m.Attributes[len(m.Attributes)-1]
+			},
+		},
+		{
+			name:   "Attribute",
+			modify: func(m *Message) { m.Attributes[0].Value = "1" },
+		},
+		{
+			name:   "Name",
+			modify: func(m *Message) { m.Name = "foo" },
+		},
+		{
+			name:   "Connection",
+			modify: func(m *Message) { m.Connection.TTL++ },
+		},
+		{
+			name:   "Bandwidth",
+			modify: func(m *Message) { m.Bandwidths[0].Value++ },
+		},
+		{
+			name:   "Timing",
+			modify: func(m *Message) { m.Timing[0].End = m.Timing[0].End.Add(time.Second) },
+		},
+		{
+			name:   "TimeZones",
+			modify: func(m *Message) { m.TZAdjustments = append(m.TZAdjustments, TimeZone{}) },
+		},
+		{
+			name:   "Medias",
+			modify: func(m *Message) { m.Medias = m.Medias[:1] },
+		},
+		{
+			name:   "MediaFormat",
+			modify: func(m *Message) { m.Medias[1].Description.Formats[0] = "100" },
+		},
+		{
+			name:   "MediaEncryption",
+			modify: func(m *Message) { m.Medias[1].Encryption.Key = "key" },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := a.Clone()
+			tc.modify(b)
+			if tc.opts.Equal(a, b) != tc.equal {
+				t.Errorf("Equal should be %v", tc.equal)
+			}
+			if tc.opts.Equal(b, a) != tc.equal {
+				t.Errorf("Equal should be symmetric")
+			}
+			if tc.inverse && a.Equal(b) {
+				t.Error("should not be equal without options")
+			}
+		})
+	}
+	t.Run("Nil", func(t *testing.T) {
+		var m *Message
+		if a.Equal(nil) || m.Equal(a) || !m.Equal(nil) {
+			t.Error("unexpected result")
+		}
+	})
+}
+
+func TestMedia_Equal(t *testing.T) {
+	a, err := Decode(loadData(t, "sdp_session_ex_full", testCRNL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := a.Medias[1].Clone()
+	if !m.Equal(&a.Medias[1]) {
+		t.Error("clone should be equal")
+	}
+	if m.Equal(&a.Medias[0]) {
+		t.Error("should not be equal")
+	}
+}
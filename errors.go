@@ -0,0 +1,172 @@
+package sdp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Possible causes of FieldError that can be checked with errors.Is.
+var (
+	ErrUnexpectedField = errors.New("unexpected field")
+	ErrMissingField    = errors.New("missing field")
+	ErrInvalidSyntax   = errors.New("invalid syntax")
+	ErrInvalidNumber   = errors.New("invalid number")
+	ErrObsoleteField   = errors.New("obsolete field")
+)
+
+// ErrorCode is machine-readable kind of FieldError.
+type ErrorCode int
+
+// Possible error codes.
+const (
+	CodeInvalidSyntax ErrorCode = iota
+	CodeUnexpectedField
+	CodeMissingField
+	CodeInvalidNumber
+	CodeInvalidIP
+	CodeObsoleteField
+	CodeLimitExceeded
+)
+
+var codeToStr = map[ErrorCode]string{
+	CodeInvalidSyntax:   "invalid syntax",
+	CodeUnexpectedField: "unexpected field",
+	CodeMissingField:    "missing field",
+	CodeInvalidNumber:   "invalid number",
+	CodeInvalidIP:       "invalid ip",
+	CodeObsoleteField:   "obsolete field",
+	CodeLimitExceeded:   "limit exceeded",
+}
+
+func (c ErrorCode) String() string {
+	s, ok := codeToStr[c]
+	if ok {
+		return s
+	}
+	return "code " + strconv.Itoa(int(c))
+}
+
+// sentinel returns exported error that corresponds to c.
+func (c ErrorCode) sentinel() error {
+	switch c {
+	case CodeUnexpectedField:
+		return ErrUnexpectedField
+	case CodeMissingField:
+		return ErrMissingField
+	case CodeInvalidNumber:
+		return ErrInvalidNumber
+	case CodeInvalidIP:
+		return ErrFailedToDecodeIP
+	case CodeObsoleteField:
+		return ErrObsoleteField
+	case CodeLimitExceeded:
+		return ErrLimitExceeded
+	default:
+		return ErrInvalidSyntax
+	}
+}
+
+// errorCode returns code for error returned from field decoder.
+func errorCode(err error) ErrorCode {
+	switch cause := errors.Cause(err).(type) {
+	case *strconv.NumError:
+		return CodeInvalidNumber
+	case *LimitError:
+		return CodeLimitExceeded
+	default:
+		if cause == ErrFailedToDecodeIP {
+			return CodeInvalidIP
+		}
+		return CodeInvalidSyntax
+	}
+}
+
+// Section names of FieldError.
+const (
+	SectionSession = "session"
+	SectionTime    = "time"
+	SectionMedia   = "media"
+)
+
+// FieldError describes failure to decode a line of SDP message.
+//
+// Errors can be checked with errors.Is against ErrUnexpectedField,
+// ErrMissingField, ErrInvalidSyntax, ErrInvalidNumber,
+// ErrFailedToDecodeIP, ErrObsoleteField and ErrLimitExceeded.
+type FieldError struct {
+	Line    int       // line number, starting from 1, or 0 if not applicable
+	Column  int       // byte column in line, starting from 1, or 0 if not applicable
+	Type    Type      // type of field
+	Section string    // SectionSession, SectionTime or SectionMedia
+	Index   int       // index of time or media description
+	Code    ErrorCode // machine-readable kind of error
+	Err     error     // underlying error
+}
+
+func (e *FieldError) Error() string {
+	place := e.Section
+	if e.Section != SectionSession {
+		place = fmt.Sprintf("%s %d", e.Section, e.Index)
+	}
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s: %v", place, e.Code, e.Err)
+	}
+	return fmt.Sprintf("%s %s at line %d, column %d: %s: %v",
+		place, e.Type, e.Line, e.Column, e.Code, e.Err,
+	)
+}
+
+// Is returns true if target is exported error that corresponds to e.Code.
+func (e *FieldError) Is(target error) bool {
+	return target == e.Code.sentinel()
+}
+
+// Unwrap returns underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorList is list of errors that is returned by Decoder if
+// DecoderOptions.AllErrors is set.
+type ErrorList []*FieldError
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d errors: ", len(l))
+	for i, e := range l {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(e.Error())
+	}
+	return b.String()
+}
+
+// Is returns true if any error of list matches target.
+func (l ErrorList) Is(target error) bool {
+	for _, e := range l {
+		if errors.Is(e, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds first error of list that matches target.
+func (l ErrorList) As(target interface{}) bool {
+	for _, e := range l {
+		if errors.As(e, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package sdp
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestErrorCode_String(t *testing.T) {
+	for code := range codeToStr {
+		if len(code.String()) < 2 {
+			t.Errorf("ErrorCode.String() %s incorrect", code)
+		}
+	}
+	if v := ErrorCode(100).String(); v != "code 100" {
+		t.Errorf("unexpected %q", v)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		code ErrorCode
+	}{
+		{errors.Wrap(ErrFailedToDecodeIP, "failed"), CodeInvalidIP},
+		{errors.Wrap(&strconv.NumError{Err: strconv.ErrSyntax}, "failed"), CodeInvalidNumber},
+		{errors.Wrap(newDecodeError("p", "r"), "failed"), CodeInvalidSyntax},
+	} {
+		if code := errorCode(tc.err); code != tc.code {
+			t.Errorf("errorCode(%v) %s != %s", tc.err, code, tc.code)
+		}
+	}
+}
+
+func TestFieldError_Error(t *testing.T) {
+	for _, tc := range []struct {
+		err FieldError
+		out string
+	}{
+		{
+			err: FieldError{
+				Line: 3, Column: 10, Type: TypeConnectionData,
+				Section: SectionSession, Code: CodeInvalidIP,
+				Err: ErrFailedToDecodeIP,
+			},
+			out: "session connection data at line 3, column 10: invalid ip: invalid IP",
+		},
+		{
+			err: FieldError{
+				Line: 12, Column: 3, Type: TypeAttribute,
+				Section: SectionMedia, Index: 1, Code: CodeInvalidSyntax,
+				Err: errors.New("bad"),
+			},
+			out: "media 1 attribute at line 12, column 3: invalid syntax: bad",
+		},
+		{
+			err: FieldError{
+				Type: TypeSessionName, Section: SectionSession,
+				Code: CodeMissingField, Err: errors.New("not set"),
+			},
+			out: "session: missing field: not set",
+		},
+	} {
+		t.Run(tc.out, func(t *testing.T) {
+			if v := tc.err.Error(); v != tc.out {
+				t.Errorf("%q != %q", v, tc.out)
+			}
+		})
+	}
+}
+
+func TestErrorList(t *testing.T) {
+	a := &FieldError{Section: SectionSession, Code: CodeMissingField, Err: errors.New("a")}
+	b := &FieldError{Section: SectionSession, Code: CodeMissingField, Err: errors.New("b")}
+	for _, tc := range []struct {
+		list ErrorList
+		out  string
+	}{
+		{nil, "no errors"},
+		{ErrorList{a}, "session: missing field: a"},
+		{ErrorList{a, b}, "2 errors: session: missing field: a; session: missing field: b"},
+	} {
+		if v := tc.list.Error(); v != tc.out {
+			t.Errorf("%q != %q", v, tc.out)
+		}
+	}
+	var err error = ErrorList{a, b}
+	if !errors.Is(err, ErrMissingField) {
+		t.Error("should match")
+	}
+	if errors.Is(err, ErrInvalidNumber) {
+		t.Error("should not match")
+	}
+	var target *FieldError
+	if !errors.As(err, &target) || target != a {
+		t.Error("should find first error")
+	}
+	if (ErrorList{}).As(&target) {
+		t.Error("blank list should not match")
+	}
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"io"
-	"io/ioutil"
 	"log"
 	"os"
 
@@ -17,7 +16,6 @@ func main() {
 	}
 	var (
 		s   sdp.Session
-		b   []byte
 		err error
 		f   io.ReadCloser
 	)
@@ -26,10 +24,7 @@ func main() {
 		log.Fatal("err:", err)
 	}
 	defer f.Close()
-	if b, err = ioutil.ReadAll(f); err != nil {
-		log.Fatal("err:", err)
-	}
-	if s, err = sdp.DecodeSession(b, s); err != nil {
+	if s, err = sdp.NewReader(f).ReadSession(s); err != nil {
 		log.Fatal("err:", err)
 	}
 	for k, v := range s {
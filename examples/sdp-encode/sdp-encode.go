@@ -30,7 +30,7 @@ func main() {
 			Protocol: "RTP/AVP",
 		},
 		Bandwidths: sdp.Bandwidths{
-			sdp.BandwidthApplicationSpecific: 66781,
+			{Type: sdp.BandwidthApplicationSpecific, Value: 66781},
 		},
 		Encryption: sdp.Encryption{
 			Method: "prompt",
@@ -46,17 +46,17 @@ func main() {
 			SessionVersion: 2890842807,
 			Address:        "10.47.16.5",
 		},
-		Name:  "SDP Seminar",
-		Info:  "A Seminar on the session description protocol",
-		URI:   "http://www.example.com/seminars/sdp.pdf",
-		Email: "j.doe@example.com (Jane Doe)",
-		Phone: "12345",
+		Name:   "SDP Seminar",
+		Info:   "A Seminar on the session description protocol",
+		URI:    "http://www.example.com/seminars/sdp.pdf",
+		Emails: []sdp.Address{{Address: "j.doe@example.com", Name: "Jane Doe"}},
+		Phones: []sdp.Address{{Address: "12345"}},
 		Connection: sdp.ConnectionData{
 			IP:  net.ParseIP("224.2.17.12"),
 			TTL: 127,
 		},
 		Bandwidths: sdp.Bandwidths{
-			sdp.BandwidthConferenceTotal: 154798,
+			{Type: sdp.BandwidthConferenceTotal, Value: 154798},
 		},
 		Timing: []sdp.Timing{
 			{
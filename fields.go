@@ -1,6 +1,7 @@
 package sdp
 
 import (
+	"bytes"
 	"fmt"
 	"net"
 	"strconv"
@@ -49,13 +50,6 @@ func appendUint(dst []byte, n int) []byte {
 	return dst
 }
 
-func appendByte(v []byte, i byte) []byte {
-	if i == 0 {
-		return appendRune(v, '0')
-	}
-	return appendUint(v, int(i))
-}
-
 func appendJoinStrings(b []byte, v ...string) []byte {
 	last := len(v) - 1
 	for i, vv := range v {
@@ -148,14 +142,19 @@ func (s Session) AddURI(uri string) Session {
 }
 
 // ConnectionData is representation for Connection Data field.
-// Only IP field is required. NetworkType and AddressType have
+// Only IP or Host field is required. NetworkType and AddressType have
 // sensible defaults.
+//
+// Address is used instead of other address fields for network types
+// other than IN, e.g. PSTN, and requires NetworkType and AddressType.
 type ConnectionData struct {
-	NetworkType string // <nettype>
-	AddressType string // <addrtype>
-	IP          net.IP // <base multicast address>
-	TTL         byte   // <ttl>
-	Addresses   byte   // <number of addresses>
+	NetworkType string         // <nettype>
+	AddressType string         // <addrtype>
+	IP          net.IP         // <base multicast address>
+	Host        string         // FQDN <connection-address>, used if IP is blank
+	TTL         int            // <ttl>, only for IP4 multicast
+	Addresses   int            // <number of addresses>
+	Address     NetworkAddress // <connection-address> of non-IN <nettype>
 }
 
 // Blank determines if ConnectionData is blank value.
@@ -174,28 +173,42 @@ func (c ConnectionData) Equal(b ConnectionData) bool {
 	if !c.IP.Equal(b.IP) {
 		return false
 	}
+	if c.Host != b.Host {
+		return false
+	}
 	if c.TTL != b.TTL {
 		return false
 	}
 	if c.Addresses != b.Addresses {
 		return false
 	}
-	return true
+	return equalNetworkAddress(c.Address, b.Address)
+}
+
+// equalNetworkAddress compares encoded a and b, because implementation
+// of NetworkAddress can be not comparable with ==.
+func equalNetworkAddress(a, b NetworkAddress) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return bytes.Equal(a.AppendTo(nil), b.AppendTo(nil))
 }
 
 const (
 	addrTypeIPv4        = "IP4"
 	addrTypeIPv6        = "IP6"
-	networkTypeInternet = "IN"
 	attributesDelimiter = ':'
 )
 
 func (c ConnectionData) getNetworkType() string {
-	return getDefault(c.NetworkType, networkTypeInternet)
+	return getDefault(c.NetworkType, NetworkTypeInternet)
 }
 
-// getAddressType returns Address Type ("addrtype") for ip,
+// getAddressType returns Address Type ("addrtype") for addr,
 // using addressType as default value if present.
+//
+// Address Type of FQDN can't be determined, so IP4 is returned, and
+// addressType should be set explicitly for IP6 hosts.
 func getAddressType(addr, addressType string) string {
 	if addressType != "" {
 		return addressType
@@ -229,6 +242,9 @@ func getAddressTypeIP(ip net.IP, addressType string) string {
 }
 
 func (c ConnectionData) getAddressType() string {
+	if c.Address != nil {
+		return getDefault(c.AddressType, AddressTypeUnknown)
+	}
 	return getAddressTypeIP(c.IP, c.AddressType)
 }
 
@@ -236,14 +252,13 @@ func (c ConnectionData) getAddressType() string {
 func (c ConnectionData) ConnectionAddress() string {
 	// <base multicast address>[/<ttl>]/<number of addresses>
 	// ALLOCATIONS: suboptimal. Use appendAddress.
-	var address = strings.ToUpper(c.IP.String())
-	if c.TTL > 0 {
-		address += fmt.Sprintf("/%d", c.TTL)
+	if c.Address != nil {
+		return string(c.Address.AppendTo(nil))
 	}
-	if c.Addresses > 0 {
-		address += fmt.Sprintf("/%d", c.Addresses)
+	if len(c.IP) == 0 && len(c.Host) > 0 {
+		return c.Host
 	}
-	return address
+	return string(c.appendAddress(nil))
 }
 
 func (c ConnectionData) String() string {
@@ -253,14 +268,22 @@ func (c ConnectionData) String() string {
 }
 
 func (c ConnectionData) appendAddress(v []byte) []byte {
+	if c.Address != nil {
+		return c.Address.AppendTo(v)
+	}
+	if len(c.IP) == 0 && len(c.Host) > 0 {
+		return append(v, c.Host...)
+	}
 	v = appendIP(v, c.IP)
-	if c.TTL > 0 {
+	// TTL is required before number of addresses for IP4 and is never
+	// used for IP6.
+	if isIPv4(c.IP) && (c.TTL > 0 || c.Addresses > 0) {
 		v = appendRune(v, '/')
-		v = appendByte(v, c.TTL)
+		v = appendInt(v, c.TTL)
 	}
 	if c.Addresses > 0 {
 		v = appendRune(v, '/')
-		v = appendByte(v, c.Addresses)
+		v = appendInt(v, c.Addresses)
 	}
 	return v
 }
@@ -277,7 +300,7 @@ type Origin struct {
 }
 
 func (o *Origin) getNetworkType() string {
-	return getDefault(o.NetworkType, networkTypeInternet)
+	return getDefault(o.NetworkType, NetworkTypeInternet)
 }
 
 func (o *Origin) getAddressType() string {
@@ -391,8 +414,47 @@ const (
 	BandwidthApplicationSpecific BandwidthType = "AS"
 	// defined in RFC 3890
 	BandwidthApplicationSpecificTransportIndependent BandwidthType = "TIAS"
+	// defined in RFC 3556
+	BandwidthRTCPSenders   BandwidthType = "RS"
+	BandwidthRTCPReceivers BandwidthType = "RR"
 )
 
+// bandwidthExperimentalPrefix is prefix of experimental <bwtype>
+// values, defined in RFC 4566 Section 5.8.
+const bandwidthExperimentalPrefix = "X-"
+
+// Registered returns true if t is one of known registered <bwtype>
+// values.
+func (t BandwidthType) Registered() bool {
+	switch t {
+	case BandwidthConferenceTotal, BandwidthApplicationSpecific,
+		BandwidthApplicationSpecificTransportIndependent,
+		BandwidthRTCPSenders, BandwidthRTCPReceivers:
+		return true
+	default:
+		return false
+	}
+}
+
+// Experimental returns true if t is experimental "X-" <bwtype>.
+func (t BandwidthType) Experimental() bool {
+	return strings.HasPrefix(string(t), bandwidthExperimentalPrefix)
+}
+
+// bitsPerUnit returns count of bits per second in unit of <bandwidth>
+// for t or 0 if unit is unknown.
+func (t BandwidthType) bitsPerUnit() int {
+	switch t {
+	case BandwidthConferenceTotal, BandwidthApplicationSpecific:
+		return 1000 // kilobits per second
+	case BandwidthApplicationSpecificTransportIndependent,
+		BandwidthRTCPSenders, BandwidthRTCPReceivers:
+		return 1 // bits per second
+	default:
+		return 0
+	}
+}
+
 // AddBandwidth appends Bandwidth field to Session.
 func (s Session) AddBandwidth(t BandwidthType, bandwidth int) Session {
 	v := make([]byte, 0, 128)
@@ -244,15 +244,6 @@ func BenchmarkAppendIP(b *testing.B) {
 	}
 }
 
-func BenchmarkAppendByte(b *testing.B) {
-	buf := make([]byte, 0, 64)
-	b.ReportAllocs()
-	for i := 0; i < b.N; i++ {
-		buf = appendByte(buf, 128)
-		buf = buf[:0]
-	}
-}
-
 func BenchmarkAppendInt(b *testing.B) {
 	buf := make([]byte, 0, 64)
 	b.ReportAllocs()
@@ -342,23 +333,6 @@ func TestAppendUint(t *testing.T) {
 	})
 }
 
-func TestAppendByte(t *testing.T) {
-	for _, tc := range []struct {
-		in  byte
-		out []byte
-	}{
-		{0, []byte("0")},
-		{1, []byte("1")},
-		{10, []byte("10")},
-	} {
-		t.Run(fmt.Sprint(tc.in), func(t *testing.T) {
-			if !bytes.Equal(appendByte(nil, tc.in), tc.out) {
-				t.Error("not equal")
-			}
-		})
-	}
-}
-
 func TestSession_AddRaw(t *testing.T) {
 	s := new(Session).AddRaw('α', "räw")
 	shouldDecodeExpS(t, s, "raw")
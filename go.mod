@@ -1,5 +1,5 @@
 module gortc.io/sdp
 
-go 1.12
+go 1.13
 
 require github.com/pkg/errors v0.9.1
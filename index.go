@@ -0,0 +1,130 @@
+package sdp
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+)
+
+// SessionIndex records boundaries of session, time and media sections
+// of Session, providing random access to them without decoding.
+//
+// Sections are sub-slices of indexed Session, so Session must not be
+// modified while index is in use.
+type SessionIndex struct {
+	s      Session
+	opts   DecoderOptions // used by DecodeMedia
+	times  []int          // positions of "t=" lines
+	medias []int          // positions of "m=" lines
+}
+
+// NewSessionIndex returns index of s, built in one pass.
+func NewSessionIndex(s Session) *SessionIndex {
+	return NewSessionIndexWithOptions(s, DecoderOptions{})
+}
+
+// NewSessionIndexWithOptions returns index of s, which decodes media
+// sections with opts.
+func NewSessionIndexWithOptions(s Session, opts DecoderOptions) *SessionIndex {
+	i := &SessionIndex{opts: opts}
+	i.Reset(s)
+	return i
+}
+
+// Reset indexes s, reusing memory of index and keeping options.
+func (i *SessionIndex) Reset(s Session) {
+	i.s = s
+	i.times = i.times[:0]
+	i.medias = i.medias[:0]
+	for pos, l := range s {
+		switch l.Type {
+		case TypeTiming:
+			if len(i.medias) == 0 {
+				i.times = append(i.times, pos)
+			}
+		case TypeMediaDescription:
+			i.medias = append(i.medias, pos)
+		}
+	}
+}
+
+// Session returns lines of session section, i.e. all lines before
+// first media section, including time descriptions.
+func (i *SessionIndex) Session() Session {
+	if len(i.medias) == 0 {
+		return i.s
+	}
+	return i.s[:i.medias[0]]
+}
+
+// Times returns count of time descriptions.
+func (i *SessionIndex) Times() int {
+	return len(i.times)
+}
+
+// Time returns lines of n-th time description, i.e. "t=" line with
+// following "r=" and "z=" lines, or nil if there is no such description.
+func (i *SessionIndex) Time(n int) Session {
+	if n < 0 || n >= len(i.times) {
+		return nil
+	}
+	start := i.times[n]
+	end := start + 1
+	for end < len(i.s) {
+		if t := i.s[end].Type; t != TypeRepeatTimes && t != TypeTimeZones {
+			break
+		}
+		end++
+	}
+	return i.s[start:end]
+}
+
+// Medias returns count of media sections.
+func (i *SessionIndex) Medias() int {
+	return len(i.medias)
+}
+
+// Media returns lines of n-th media section, starting from "m=" line,
+// or nil if there is no such section.
+func (i *SessionIndex) Media(n int) Session {
+	if n < 0 || n >= len(i.medias) {
+		return nil
+	}
+	if n == len(i.medias)-1 {
+		return i.s[i.medias[n]:]
+	}
+	return i.s[i.medias[n]:i.medias[n+1]]
+}
+
+// DecodeMedia decodes n-th media section to m with options of index.
+func (i *SessionIndex) DecodeMedia(n int, m *Media) error {
+	s := i.Media(n)
+	if s == nil {
+		return errors.Errorf("no media section %d", n)
+	}
+	d := NewDecoderWithOptions(s, i.opts)
+	return d.DecodeMedia(m)
+}
+
+// Attribute returns value of first attribute with key k in s and true,
+// or nil and false if there is no such attribute. Value references
+// memory of s.
+//
+// Use it on sections of SessionIndex to look up attributes without
+// decoding.
+func (s Session) Attribute(k string) ([]byte, bool) {
+	for _, l := range s {
+		if l.Type != TypeAttribute || !bytes.HasPrefix(l.Value, []byte(k)) {
+			continue
+		}
+		v := l.Value[len(k):]
+		if len(v) == 0 {
+			// Flag.
+			return v, true
+		}
+		if v[0] == attributesDelimiter {
+			return v[1:], true
+		}
+	}
+	return nil, false
+}
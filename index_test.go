@@ -0,0 +1,190 @@
+package sdp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestSessionIndex(t *testing.T) {
+	data := loadData(t, "sdp_session_ex_full", testCRNL)
+	s, err := DecodeSession(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := NewSessionIndex(s)
+	t.Run("Session", func(t *testing.T) {
+		session := i.Session()
+		if len(session) != 14 || session[len(session)-1].Type != TypeAttribute {
+			t.Errorf("unexpected session section: %v", session)
+		}
+		if v, ok := session.Attribute("recvonly"); !ok || len(v) != 0 {
+			t.Error("flag not found")
+		}
+		if _, ok := session.Attribute("rtpmap"); ok {
+			t.Error("media attribute should not be found")
+		}
+	})
+	t.Run("Time", func(t *testing.T) {
+		if i.Times() != 1 {
+			t.Fatalf("unexpected times count %d", i.Times())
+		}
+		tm := i.Time(0)
+		if len(tm) != 2 || tm[0].Type != TypeTiming || tm[1].Type != TypeRepeatTimes {
+			t.Errorf("unexpected time description: %v", tm)
+		}
+		if i.Time(1) != nil || i.Time(-1) != nil {
+			t.Error("out of range time description should be nil")
+		}
+	})
+	t.Run("Media", func(t *testing.T) {
+		if i.Medias() != 2 {
+			t.Fatalf("unexpected medias count %d", i.Medias())
+		}
+		for n := 0; n < i.Medias(); n++ {
+			section := i.Media(n)
+			if section[0].Type != TypeMediaDescription {
+				t.Errorf("media %d starts with %s", n, section[0].Type)
+			}
+			var m Media
+			if err := i.DecodeMedia(n, &m); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(m, expected.Medias[n]) {
+				t.Errorf("media %d: %+v != %+v", n, m, expected.Medias[n])
+			}
+		}
+		if len(i.Media(0)) != 2 || len(i.Media(1)) != 4 {
+			t.Errorf("unexpected sizes %d, %d", len(i.Media(0)), len(i.Media(1)))
+		}
+		if v, ok := i.Media(1).Attribute("rtpmap"); !ok || string(v) != "99 h263-1998/90000" {
+			t.Errorf("unexpected rtpmap %q", v)
+		}
+		if _, ok := i.Media(1).Attribute("rtp"); ok {
+			t.Error("prefix of key should not match")
+		}
+		if i.Media(2) != nil || i.Media(-1) != nil {
+			t.Error("out of range media should be nil")
+		}
+		if err := i.DecodeMedia(2, new(Media)); err == nil {
+			t.Error("should fail")
+		}
+	})
+	t.Run("Options", func(t *testing.T) {
+		session, err := DecodeSession([]byte("v=0\nm=audio 49170 RTP/AVP 0 8\n"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = NewSessionIndex(session).DecodeMedia(0, new(Media)); err != nil {
+			t.Fatal(err)
+		}
+		i := NewSessionIndexWithOptions(session, DecoderOptions{Limits: Limits{Formats: 1}})
+		i.Reset(session)
+		if err = i.DecodeMedia(0, new(Media)); !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+	t.Run("Lossless", func(t *testing.T) {
+		data := loadData(t, "sdp_session_ex_media_unknown_type", testCRNL)
+		session, err := DecodeSession(data, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts := DecoderOptions{Lossless: true}
+		d := NewDecoderWithOptions(session, opts)
+		m := new(Message)
+		if err = d.Decode(m); err != nil {
+			t.Fatal(err)
+		}
+		media := new(Media)
+		if err = NewSessionIndexWithOptions(session, opts).DecodeMedia(0, media); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(media.Raw, m.Medias[0].Raw) {
+			t.Errorf("%#v != %#v", media.Raw, m.Medias[0].Raw)
+		}
+		m.Medias[0] = *media
+		if out := m.Append(nil).AppendTo(nil); !reflect.DeepEqual(out, data) {
+			t.Errorf("not equal:\n%s\n!=\n%s", out, data)
+		}
+	})
+	t.Run("NoMedia", func(t *testing.T) {
+		session := s[:3]
+		i := NewSessionIndex(session)
+		if i.Medias() != 0 || i.Times() != 0 || len(i.Session()) != 3 {
+			t.Error("unexpected index")
+		}
+	})
+	t.Run("ZeroAllocs", func(t *testing.T) {
+		webrtc, err := DecodeSession(loadData(t, "spd_session_ex_webrtc1", testCRNL), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		allocs := testing.AllocsPerRun(10, func() {
+			i.Reset(webrtc)
+			if v, ok := i.Media(0).Attribute("mid"); !ok || string(v) != "data" {
+				t.Fatal("mid not found")
+			}
+		})
+		if allocs > 0 {
+			t.Errorf("allocs %v > 0", allocs)
+		}
+	})
+}
+
+func TestDecoder_DecodeMedia(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+	}{
+		{"Blank", ""},
+		{"NoMedia", "v=0\n"},
+		{"SecondMedia", "m=audio 1 RTP/AVP 0\nm=video 2 RTP/AVP 96\n"},
+		{"BadPort", "m=audio x RTP/AVP 0\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := DecodeSession([]byte(tc.in), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := NewDecoder(s)
+			if err = d.DecodeMedia(new(Media)); err == nil {
+				t.Error("should fail")
+			}
+		})
+	}
+	t.Run("AllErrors", func(t *testing.T) {
+		s, err := DecodeSession([]byte("m=audio 1 RTP/AVP 0\nc=IN IP4 x\na=key:\n"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := NewDecoderWithOptions(s, DecoderOptions{AllErrors: true})
+		err = d.DecodeMedia(new(Media))
+		if list, ok := err.(ErrorList); !ok || len(list) != 2 {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	t.Run("Reuse", func(t *testing.T) {
+		s, err := DecodeSession([]byte("m=audio 1 RTP/AVP 0 8\na=sendonly\n"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := Media{Title: "old"}
+		m.AddFlag("recvonly")
+		d := NewDecoder(s)
+		if err = d.DecodeMedia(&m); err != nil {
+			t.Fatal(err)
+		}
+		if m.Title != "" || m.Flag("recvonly") || !m.Flag("sendonly") {
+			t.Errorf("unexpected media: %+v", m)
+		}
+		if len(m.Description.Formats) != 2 {
+			t.Errorf("unexpected formats: %v", m.Description.Formats)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package sdp
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ErrLimitExceeded means that input exceeds one of Limits. Use
+// errors.Is to check for it.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// Limits bound resources that are used to decode untrusted input.
+//
+// Zero field means that limit from DefaultLimits is used, and negative
+// field disables the limit.
+type Limits struct {
+	Lines      int // lines in session
+	LineLength int // bytes in line, including type and "="
+	Medias     int // media descriptions in message
+	Attributes int // attributes in session or media description
+	Formats    int // formats in media description
+	Offsets    int // offsets in repeat times
+}
+
+// DefaultLimits are limits that are sufficient for any sane SDP.
+var DefaultLimits = Limits{
+	Lines:      4096,
+	LineLength: 4096,
+	Medias:     256,
+	Attributes: 1024,
+	Formats:    128,
+	Offsets:    64,
+}
+
+func limit(v, defaultValue int) int {
+	switch {
+	case v == 0:
+		return defaultValue
+	case v < 0:
+		return 0
+	default:
+		return v
+	}
+}
+
+// withDefaults returns l with zero fields set from DefaultLimits and
+// negative fields set to zero.
+func (l Limits) withDefaults() Limits {
+	return Limits{
+		Lines:      limit(l.Lines, DefaultLimits.Lines),
+		LineLength: limit(l.LineLength, DefaultLimits.LineLength),
+		Medias:     limit(l.Medias, DefaultLimits.Medias),
+		Attributes: limit(l.Attributes, DefaultLimits.Attributes),
+		Formats:    limit(l.Formats, DefaultLimits.Formats),
+		Offsets:    limit(l.Offsets, DefaultLimits.Offsets),
+	}
+}
+
+// exceeds returns true if n is greater than max, where zero max is
+// interpreted as no limit.
+func exceeds(n, max int) bool {
+	return max > 0 && n > max
+}
+
+// LimitError describes exceeded limit.
+type LimitError struct {
+	Limit string // name of field of Limits
+	Max   int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s limit of %d exceeded", e.Limit, e.Max)
+}
+
+// Is returns true for ErrLimitExceeded.
+func (e *LimitError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// DecodeSession is DecodeSession that fails with *LimitError if
+// lines count or length exceeds l.
+func (l Limits) DecodeSession(b []byte, s Session) (Session, error) {
+	l = l.withDefaults()
+	var (
+		err   error
+		lines int
+	)
+	scanner := newScanner(b)
+	for scanner.Scan() {
+		lines++
+		if exceeds(lines, l.Lines) {
+			err = &LimitError{Limit: "Lines", Max: l.Lines}
+			return s, errors.Wrapf(err, "failed to decode line %d", lines)
+		}
+		if exceeds(len(scanner.Line()), l.LineLength) {
+			err = &LimitError{Limit: "LineLength", Max: l.LineLength}
+			return s, errors.Wrapf(err, "failed to decode line %d", lines)
+		}
+		if s, err = s.appendDecoded(scanner.Line()); err != nil {
+			break
+		}
+	}
+	return s, err
+}
@@ -0,0 +1,159 @@
+package sdp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestLimits_withDefaults(t *testing.T) {
+	l := Limits{Lines: 10, Medias: -1}.withDefaults()
+	if l.Lines != 10 {
+		t.Error("explicit limit should be used")
+	}
+	if l.Medias != 0 {
+		t.Error("negative limit should be disabled")
+	}
+	if l.Attributes != DefaultLimits.Attributes {
+		t.Error("default limit should be used")
+	}
+}
+
+func TestLimits_DecodeSession(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		limits Limits
+		in     string
+		limit  string
+	}{
+		{"Lines", Limits{Lines: 2}, "v=0\ns=-\ni=x\n", "Lines"},
+		{"LineLength", Limits{LineLength: 5}, "v=0\ns=abcd\n", "LineLength"},
+		{"Default", Limits{}, "v=0\ni=" + strings.Repeat("x", 4096) + "\n", "LineLength"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.limits.DecodeSession([]byte(tc.in), nil)
+			if !errors.Is(err, ErrLimitExceeded) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var limitErr *LimitError
+			if !errors.As(err, &limitErr) || limitErr.Limit != tc.limit {
+				t.Errorf("unexpected limit error: %v", err)
+			}
+		})
+	}
+	t.Run("Disabled", func(t *testing.T) {
+		in := "v=0\ni=" + strings.Repeat("x", 4096) + "\n"
+		if _, err := (Limits{LineLength: -1}).DecodeSession([]byte(in), nil); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestDecoder_Limits(t *testing.T) {
+	const prefix = "v=0\no=- 1 2 IN IP4 127.0.0.1\ns=-\nt=0 0\n"
+	for _, tc := range []struct {
+		name   string
+		limits Limits
+		in     string
+		limit  string
+		line   int
+	}{
+		{
+			name:   "Lines",
+			limits: Limits{Lines: 4},
+			in:     prefix + "a=sendonly\n",
+			limit:  "Lines",
+			line:   5,
+		},
+		{
+			name:   "LineLength",
+			limits: Limits{LineLength: 20},
+			in:     prefix + "a=sendonly\n",
+			limit:  "LineLength",
+			line:   2,
+		},
+		{
+			name:   "Medias",
+			limits: Limits{Medias: 1},
+			in:     prefix + "m=audio 1 RTP/AVP 0\nm=video 2 RTP/AVP 96\n",
+			limit:  "Medias",
+			line:   6,
+		},
+		{
+			name:   "SessionAttributes",
+			limits: Limits{Attributes: 2},
+			in:     prefix + "a=a\na=b\na=c\n",
+			limit:  "Attributes",
+			line:   7,
+		},
+		{
+			name:   "MediaAttributes",
+			limits: Limits{Attributes: 2},
+			in: prefix + "a=a\na=b\nm=audio 1 RTP/AVP 0\na=a\na=b\n" +
+				"m=video 2 RTP/AVP 96\na=a\na=b\na=c\n",
+			limit: "Attributes",
+			line:  13,
+		},
+		{
+			name:   "Formats",
+			limits: Limits{Formats: 2},
+			in:     prefix + "m=audio 1 RTP/AVP 0 8 9\n",
+			limit:  "Formats",
+			line:   5,
+		},
+		{
+			name:   "Offsets",
+			limits: Limits{Offsets: 1},
+			in:     prefix + "r=7d 1h 0 25h\n",
+			limit:  "Offsets",
+			line:   5,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := DecodeSession([]byte(tc.in), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, allErrors := range []bool{false, true} {
+				d := NewDecoderWithOptions(s, DecoderOptions{
+					Limits:    tc.limits,
+					AllErrors: allErrors,
+				})
+				err = d.Decode(new(Message))
+				var fieldErr *FieldError
+				if !errors.As(err, &fieldErr) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !errors.Is(err, ErrLimitExceeded) || fieldErr.Code != CodeLimitExceeded {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				var limitErr *LimitError
+				if !errors.As(err, &limitErr) || limitErr.Limit != tc.limit {
+					t.Errorf("unexpected limit error: %v", err)
+				}
+				if fieldErr.Line != tc.line {
+					t.Errorf("line %d != %d", fieldErr.Line, tc.line)
+				}
+			}
+			d := NewDecoderWithOptions(s, DecoderOptions{Limits: Limits{
+				Lines:      -1,
+				LineLength: -1,
+				Medias:     -1,
+				Attributes: -1,
+				Formats:    -1,
+				Offsets:    -1,
+			}})
+			if err = d.Decode(new(Message)); err != nil {
+				t.Errorf("unlimited decoding failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestLimitError_Error(t *testing.T) {
+	err := &LimitError{Limit: "Medias", Max: 10}
+	if v := err.Error(); v != "Medias limit of 10 exceeded" {
+		t.Errorf("unexpected %q", v)
+	}
+}
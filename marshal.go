@@ -0,0 +1,620 @@
+package sdp
+
+import (
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Possible errors of Marshal and Unmarshal.
+var (
+	ErrInvalidTag      = errors.New("invalid sdp struct tag")
+	ErrUnsupportedType = errors.New("unsupported type")
+)
+
+// fieldTag is parsed `sdp:"<line>[=<name>|.<name>][,multi]"` struct tag.
+type fieldTag struct {
+	line  string // like "a", "m" or "o"
+	name  string // like "ptime" for "a=ptime" or "port" for "m.port"
+	sub   bool   // name is subfield of line, like "m.port"
+	multi bool   // all values of attribute are mapped to slice
+}
+
+func (t fieldTag) String() string {
+	switch {
+	case t.name == "":
+		return t.line
+	case t.sub:
+		return t.line + "." + t.name
+	default:
+		return t.line + "=" + t.name
+	}
+}
+
+func parseTag(tag string) (fieldTag, error) {
+	var t fieldTag
+	opts := strings.Split(tag, ",")
+	for _, opt := range opts[1:] {
+		if opt != "multi" {
+			return t, errors.Wrapf(ErrInvalidTag, "unknown option %q", opt)
+		}
+		t.multi = true
+	}
+	t.line = opts[0]
+	if i := strings.IndexAny(t.line, "=."); i >= 0 {
+		t.line, t.name, t.sub = t.line[:i], t.line[i+1:], t.line[i] == '.'
+	}
+	valid := false
+	switch t.line {
+	case "a", "b":
+		valid = !t.sub && t.name != ""
+	case "m":
+		valid = !t.sub || t.name != ""
+	case "o":
+		valid = t.sub && t.name != ""
+	case "s", "i", "u", "c":
+		valid = t.name == ""
+	}
+	if !valid || (t.multi && t.line != "a") {
+		return t, errors.Wrapf(ErrInvalidTag, "%q", tag)
+	}
+	return t, nil
+}
+
+// structValue returns struct that v points to.
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return rv, errors.Wrapf(ErrUnsupportedType, "%T is not pointer to struct", v)
+	}
+	return rv.Elem(), nil
+}
+
+// Marshal returns Message that is described by struct that v points
+// to, using "sdp" struct tags of its fields, like
+//
+//	type Stream struct {
+//		Port    int      `sdp:"m.port"`
+//		PTime   int      `sdp:"a=ptime"`
+//		RTPMaps []RTPMap `sdp:"a=rtpmap,multi"`
+//	}
+//
+//	type Session struct {
+//		Name    string   `sdp:"s"`
+//		Streams []Stream `sdp:"m=audio"`
+//	}
+//
+// Supported tags are:
+//
+//	s, i, u, o.username, o.sess-id, o.sess-version, o.address
+//	c           connection address, string or ConnectionData
+//	b=<bwtype>  bandwidth value, integer
+//	a=<key>     attribute, flag if bool, multi for all values
+//	m           media descriptions, struct or slice of structs,
+//	            m=<media> also sets or matches media type
+//	m.type, m.port, m.ports, m.proto, m.fmt
+//
+// Media structs use same tags, where "i" is media title. Attribute
+// values are string, integer, type with AttributeCodec registered
+// for attribute key or pointer to one of them. Zero attribute, bandwidth and media connection
+// values are omitted. Fields without tags are ignored, except embedded
+// structs that are traversed.
+func Marshal(v interface{}) (*Message, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+	m := new(Message)
+	if err = marshalStruct(rv, m, nil); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Unmarshal sets fields of struct that v points to from m, using same
+// struct tags as Marshal. Attribute, bandwidth, media connection and
+// media fields that have no corresponding values in m are not changed,
+// so bool field is only set to true if flag is present. Pointer fields
+// are allocated if needed.
+//
+// Strings are copied, so v does not share memory with m.
+func Unmarshal(m *Message, v interface{}) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	return unmarshalStruct(m, nil, rv)
+}
+
+// walkStruct calls f for every tagged field of struct rv, traversing
+// untagged embedded structs.
+func walkStruct(rv reflect.Value, f func(t fieldTag, v reflect.Value) error) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, ok := field.Tag.Lookup("sdp")
+		if tag == "-" {
+			continue
+		}
+		if !ok {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				if err := walkStruct(rv.Field(i), f); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			return errors.Wrapf(ErrInvalidTag, "unexported field %s", field.Name)
+		}
+		t, err := parseTag(tag)
+		if err != nil {
+			return errors.Wrapf(err, "field %s", field.Name)
+		}
+		if err = f(t, rv.Field(i)); err != nil {
+			return errors.Wrapf(err, "field %s", field.Name)
+		}
+	}
+	return nil
+}
+
+// isMediaStruct reports whether t is struct or pointer to struct that
+// can be used with "m" tag.
+func isMediaStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+var connectionDataType = reflect.TypeOf(ConnectionData{})
+
+func marshalStruct(rv reflect.Value, m *Message, media *Media) error {
+	return walkStruct(rv, func(t fieldTag, v reflect.Value) error {
+		if media == nil {
+			return marshalSessionField(t, v, m)
+		}
+		return marshalMediaField(t, v, media)
+	})
+}
+
+func marshalSessionField(t fieldTag, v reflect.Value, m *Message) error {
+	var err error
+	switch t.line + "." + t.name {
+	case "s.":
+		return setString(&m.Name, v)
+	case "i.":
+		return setString(&m.Info, v)
+	case "u.":
+		return setString(&m.URI, v)
+	case "o.username":
+		return setString(&m.Origin.Username, v)
+	case "o.address":
+		return setString(&m.Origin.Address, v)
+	case "o.sess-id":
+		return setInt64(&m.Origin.SessionID, v)
+	case "o.sess-version":
+		return setInt64(&m.Origin.SessionVersion, v)
+	case "c.":
+		return marshalConnection(&m.Connection, v)
+	}
+	switch t.line {
+	case "a":
+		m.Attributes, err = marshalAttribute(m.Attributes, t, v)
+		return err
+	case "b":
+		m.Bandwidths, err = marshalBandwidth(m.Bandwidths, t, v)
+		return err
+	case "m":
+		if !t.sub {
+			return marshalMedias(m, t, v)
+		}
+	}
+	return errors.Wrapf(ErrInvalidTag, "%s is not session-level", t)
+}
+
+func marshalMediaField(t fieldTag, v reflect.Value, media *Media) error {
+	var err error
+	switch t.line + "." + t.name {
+	case "i.":
+		return setString(&media.Title, v)
+	case "c.":
+		if v.IsZero() {
+			return nil
+		}
+		return marshalConnection(media.addConnection(), v)
+	case "m.type":
+		return setString(&media.Description.Type, v)
+	case "m.proto":
+		return setString(&media.Description.Protocol, v)
+	case "m.port":
+		return setInt(&media.Description.Port, v)
+	case "m.ports":
+		return setInt(&media.Description.PortsNumber, v)
+	case "m.fmt":
+		return setStrings(&media.Description.Formats, v)
+	}
+	switch t.line {
+	case "a":
+		media.Attributes, err = marshalAttribute(media.Attributes, t, v)
+		return err
+	case "b":
+		media.Bandwidths, err = marshalBandwidth(media.Bandwidths, t, v)
+		return err
+	}
+	return errors.Wrapf(ErrInvalidTag, "%s is not media-level", t)
+}
+
+func marshalMedias(m *Message, t fieldTag, v reflect.Value) error {
+	if v.Kind() != reflect.Slice {
+		v = reflect.Append(reflect.MakeSlice(reflect.SliceOf(v.Type()), 0, 1), v)
+	}
+	if !isMediaStruct(v.Type().Elem()) {
+		return errors.Wrapf(ErrUnsupportedType, "%s", v.Type())
+	}
+	for i := 0; i < v.Len(); i++ {
+		e := v.Index(i)
+		if e.Kind() == reflect.Ptr {
+			if e.IsNil() {
+				continue
+			}
+			e = e.Elem()
+		}
+		media := Media{Description: MediaDescription{Type: t.name}}
+		if err := marshalStruct(e, m, &media); err != nil {
+			return err
+		}
+		m.Medias = append(m.Medias, media)
+	}
+	return nil
+}
+
+func marshalConnection(c *ConnectionData, v reflect.Value) error {
+	switch {
+	case v.Type() == connectionDataType:
+		*c = v.Interface().(ConnectionData)
+	case v.Kind() == reflect.String:
+		if ip := net.ParseIP(v.String()); ip != nil {
+			c.IP = ip
+		} else {
+			c.Host = v.String()
+		}
+	default:
+		return errors.Wrapf(ErrUnsupportedType, "%s", v.Type())
+	}
+	return nil
+}
+
+func marshalBandwidth(b Bandwidths, t fieldTag, v reflect.Value) (Bandwidths, error) {
+	var value int
+	if err := setInt(&value, v); err != nil {
+		return b, err
+	}
+	if value == 0 {
+		return b, nil
+	}
+	return b.Set(BandwidthType(t.name), value), nil
+}
+
+func marshalAttribute(a Attributes, t fieldTag, v reflect.Value) (Attributes, error) {
+	if !t.multi {
+		return marshalAttributeValue(a, t.name, v)
+	}
+	if v.Kind() != reflect.Slice {
+		return a, errors.Wrapf(ErrUnsupportedType, "%s with multi", v.Type())
+	}
+	var err error
+	for i := 0; i < v.Len(); i++ {
+		if a, err = marshalAttributeValue(a, t.name, v.Index(i)); err != nil {
+			return a, err
+		}
+	}
+	return a, nil
+}
+
+func marshalAttributeValue(a Attributes, key string, v reflect.Value) (Attributes, error) {
+	if v.IsZero() {
+		return a, nil
+	}
+	if v.Kind() == reflect.Ptr {
+		return marshalAttributeValue(a, key, v.Elem())
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return addAttribute(a, key, blank), nil
+	case reflect.String:
+		if err := validateByteString(key, v.String()); err != nil {
+			return a, err
+		}
+		return addAttribute(a, key, v.String()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return addAttribute(a, key, strconv.FormatInt(v.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return addAttribute(a, key, strconv.FormatUint(v.Uint(), 10)), nil
+	}
+	c, err := lookupAttribute(key)
+	if err != nil {
+		return a, err
+	}
+	return a.encode(c, key, v.Interface())
+}
+
+func unmarshalStruct(m *Message, media *Media, rv reflect.Value) error {
+	return walkStruct(rv, func(t fieldTag, v reflect.Value) error {
+		if media == nil {
+			return unmarshalSessionField(m, t, v)
+		}
+		return unmarshalMediaField(media, t, v)
+	})
+}
+
+func unmarshalSessionField(m *Message, t fieldTag, v reflect.Value) error {
+	switch t.line + "." + t.name {
+	case "s.":
+		return getString(v, m.Name)
+	case "i.":
+		return getString(v, m.Info)
+	case "u.":
+		return getString(v, m.URI)
+	case "o.username":
+		return getString(v, m.Origin.Username)
+	case "o.address":
+		return getString(v, m.Origin.Address)
+	case "o.sess-id":
+		return getInt(v, m.Origin.SessionID)
+	case "o.sess-version":
+		return getInt(v, m.Origin.SessionVersion)
+	case "c.":
+		return unmarshalConnection(v, m.Connection)
+	}
+	switch t.line {
+	case "a":
+		return unmarshalAttribute(m.Attributes, t, v)
+	case "b":
+		return unmarshalBandwidth(m.Bandwidths, t, v)
+	case "m":
+		if !t.sub {
+			return unmarshalMedias(m, t, v)
+		}
+	}
+	return errors.Wrapf(ErrInvalidTag, "%s is not session-level", t)
+}
+
+func unmarshalMediaField(media *Media, t fieldTag, v reflect.Value) error {
+	switch t.line + "." + t.name {
+	case "i.":
+		return getString(v, media.Title)
+	case "c.":
+		if len(media.Connections) == 0 {
+			return nil
+		}
+		return unmarshalConnection(v, media.Connections[0])
+	case "m.type":
+		return getString(v, media.Description.Type)
+	case "m.proto":
+		return getString(v, media.Description.Protocol)
+	case "m.port":
+		return getInt(v, int64(media.Description.Port))
+	case "m.ports":
+		return getInt(v, int64(media.Description.PortsNumber))
+	case "m.fmt":
+		return getStrings(v, media.Description.Formats)
+	}
+	switch t.line {
+	case "a":
+		return unmarshalAttribute(media.Attributes, t, v)
+	case "b":
+		return unmarshalBandwidth(media.Bandwidths, t, v)
+	}
+	return errors.Wrapf(ErrInvalidTag, "%s is not media-level", t)
+}
+
+func unmarshalMedias(m *Message, t fieldTag, v reflect.Value) error {
+	var medias []int
+	for i := range m.Medias {
+		if t.name == "" || m.Medias[i].Description.Type == t.name {
+			medias = append(medias, i)
+		}
+	}
+	elemType := v.Type()
+	if v.Kind() == reflect.Slice {
+		elemType = elemType.Elem()
+	}
+	if !isMediaStruct(elemType) {
+		return errors.Wrapf(ErrUnsupportedType, "%s", v.Type())
+	}
+	unmarshalElem := func(i int, e reflect.Value) error {
+		if e.Kind() == reflect.Ptr {
+			if e.IsNil() {
+				e.Set(reflect.New(e.Type().Elem()))
+			}
+			e = e.Elem()
+		}
+		return unmarshalStruct(m, &m.Medias[i], e)
+	}
+	if v.Kind() != reflect.Slice {
+		if len(medias) == 0 {
+			return nil
+		}
+		return unmarshalElem(medias[0], v)
+	}
+	s := reflect.MakeSlice(v.Type(), len(medias), len(medias))
+	for i, j := range medias {
+		if err := unmarshalElem(j, s.Index(i)); err != nil {
+			return err
+		}
+	}
+	v.Set(s)
+	return nil
+}
+
+func unmarshalConnection(v reflect.Value, c ConnectionData) error {
+	switch {
+	case v.Type() == connectionDataType:
+		v.Set(reflect.ValueOf(c.clone()))
+		return nil
+	case v.Kind() == reflect.String:
+		if len(c.IP) > 0 {
+			return getString(v, c.IP.String())
+		}
+		return getString(v, c.Host)
+	default:
+		return errors.Wrapf(ErrUnsupportedType, "%s", v.Type())
+	}
+}
+
+func unmarshalBandwidth(b Bandwidths, t fieldTag, v reflect.Value) error {
+	value, ok := b.Get(BandwidthType(t.name))
+	if !ok {
+		return nil
+	}
+	return getInt(v, int64(value))
+}
+
+func unmarshalAttribute(a Attributes, t fieldTag, v reflect.Value) error {
+	if !t.multi {
+		if v.Kind() == reflect.Bool {
+			if a.Flag(t.name) {
+				v.SetBool(true)
+			}
+			return nil
+		}
+		for _, attr := range a {
+			if attr.Key == t.name {
+				return unmarshalAttributeValue(v, t.name, attr.Value)
+			}
+		}
+		return nil
+	}
+	if v.Kind() != reflect.Slice {
+		return errors.Wrapf(ErrUnsupportedType, "%s with multi", v.Type())
+	}
+	values := a.Values(t.name)
+	if len(values) == 0 {
+		return nil
+	}
+	s := reflect.MakeSlice(v.Type(), len(values), len(values))
+	for i, value := range values {
+		if err := unmarshalAttributeValue(s.Index(i), t.name, value); err != nil {
+			return err
+		}
+	}
+	v.Set(s)
+	return nil
+}
+
+func unmarshalAttributeValue(v reflect.Value, key, value string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		e := reflect.New(v.Type().Elem())
+		if err := unmarshalAttributeValue(e.Elem(), key, value); err != nil {
+			return err
+		}
+		v.Set(e)
+		return nil
+	case reflect.String:
+		v.SetString(cloneString(value))
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return errors.Wrapf(ErrInvalidNumber, "%s %q", key, value)
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return errors.Wrapf(ErrInvalidNumber, "%s %q", key, value)
+		}
+		v.SetUint(n)
+		return nil
+	}
+	c, err := lookupAttribute(key)
+	if err != nil {
+		return err
+	}
+	// Decoded message can reference mutable memory of decoder.
+	return c.Decode(cloneString(value), v.Addr().Interface())
+}
+
+func setString(dst *string, v reflect.Value) error {
+	if v.Kind() != reflect.String {
+		return errors.Wrapf(ErrUnsupportedType, "%s", v.Type())
+	}
+	*dst = v.String()
+	return nil
+}
+
+func setStrings(dst *[]string, v reflect.Value) error {
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.String {
+		return errors.Wrapf(ErrUnsupportedType, "%s", v.Type())
+	}
+	*dst = make([]string, v.Len())
+	for i := range *dst {
+		(*dst)[i] = v.Index(i).String()
+	}
+	return nil
+}
+
+func setInt64(dst *int64, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		*dst = v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		*dst = int64(v.Uint())
+	default:
+		return errors.Wrapf(ErrUnsupportedType, "%s", v.Type())
+	}
+	return nil
+}
+
+func setInt(dst *int, v reflect.Value) error {
+	var n int64
+	if err := setInt64(&n, v); err != nil {
+		return err
+	}
+	*dst = int(n)
+	return nil
+}
+
+func getString(v reflect.Value, s string) error {
+	if v.Kind() != reflect.String {
+		return errors.Wrapf(ErrUnsupportedType, "%s", v.Type())
+	}
+	v.SetString(cloneString(s))
+	return nil
+}
+
+func getStrings(v reflect.Value, s []string) error {
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.String {
+		return errors.Wrapf(ErrUnsupportedType, "%s", v.Type())
+	}
+	e := reflect.MakeSlice(v.Type(), len(s), len(s))
+	for i := range s {
+		e.Index(i).SetString(cloneString(s[i]))
+	}
+	v.Set(e)
+	return nil
+}
+
+func getInt(v reflect.Value, n int64) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.OverflowInt(n) {
+			return errors.Wrapf(ErrInvalidNumber, "%d overflows %s", n, v.Type())
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n < 0 || v.OverflowUint(uint64(n)) {
+			return errors.Wrapf(ErrInvalidNumber, "%d overflows %s", n, v.Type())
+		}
+		v.SetUint(uint64(n))
+	default:
+		return errors.Wrapf(ErrUnsupportedType, "%s", v.Type())
+	}
+	return nil
+}
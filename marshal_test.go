@@ -0,0 +1,295 @@
+package sdp
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+type testStream struct {
+	Port      int            `sdp:"m.port"`
+	Protocol  string         `sdp:"m.proto"`
+	Formats   []string       `sdp:"m.fmt"`
+	Title     string         `sdp:"i"`
+	PTime     int            `sdp:"a=ptime"`
+	RecvOnly  bool           `sdp:"a=recvonly"`
+	RTPMaps   []RTPMap       `sdp:"a=rtpmap,multi"`
+	Feedback  []RTCPFeedback `sdp:"a=rtcp-fb,multi"`
+	Bandwidth int            `sdp:"b=AS"`
+	Address   string         `sdp:"c"`
+	Ignored   string
+}
+
+type testOrigin struct {
+	Username string `sdp:"o.username"`
+	ID       int64  `sdp:"o.sess-id"`
+	Version  uint32 `sdp:"o.sess-version"`
+	Address  string `sdp:"o.address"`
+}
+
+type testSession struct {
+	testOrigin
+	Name       string         `sdp:"s"`
+	Info       string         `sdp:"i"`
+	Connection ConnectionData `sdp:"c"`
+	Tool       string         `sdp:"a=tool"`
+	Audio      []testStream   `sdp:"m=audio"`
+	Video      *testStream    `sdp:"m=video"`
+	Skipped    string         `sdp:"-"`
+}
+
+func TestMarshal(t *testing.T) {
+	v := testSession{
+		testOrigin: testOrigin{Username: "jdoe", ID: 2890844526, Version: 2, Address: "10.47.16.5"},
+		Name:       "SDP Seminar",
+		Connection: ConnectionData{IP: net.IPv4(224, 2, 17, 12), TTL: 127},
+		Tool:       "marshal",
+		Audio: []testStream{
+			{
+				Port: 49170, Protocol: "RTP/AVP", Formats: []string{"0", "96"},
+				PTime: 20, RecvOnly: true,
+				RTPMaps: []RTPMap{
+					{PayloadType: 0, Encoding: "PCMU", ClockRate: 8000},
+					{PayloadType: 96, Encoding: "opus", ClockRate: 48000, Channels: 2},
+				},
+			},
+		},
+		Video: &testStream{
+			Port: 51372, Protocol: "RTP/AVP", Formats: []string{"99"},
+			Title:     "Camera",
+			Feedback:  []RTCPFeedback{{PayloadType: "99", Type: "nack", Parameter: "pli"}},
+			RTPMaps:   []RTPMap{{PayloadType: 99, Encoding: "h263-1998", ClockRate: 90000}},
+			Bandwidth: 128, Address: "example.com",
+		},
+		Skipped: "skipped",
+	}
+	m, err := Marshal(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Origin.SessionID != 2890844526 || m.Name != "SDP Seminar" || m.Attributes.Value("tool") != "marshal" {
+		t.Error("unexpected session", m)
+	}
+	if len(m.Medias) != 2 {
+		t.Fatal("unexpected medias count", len(m.Medias))
+	}
+	audio := Media{
+		Description: MediaDescription{Type: "audio", Port: 49170, Protocol: "RTP/AVP", Formats: []string{"0", "96"}},
+		Attributes: Attributes{
+			{Key: "ptime", Value: "20"},
+			{Key: "recvonly"},
+			{Key: "rtpmap", Value: "0 PCMU/8000"},
+			{Key: "rtpmap", Value: "96 opus/48000/2"},
+		},
+	}
+	if !m.Medias[0].Equal(&audio) {
+		t.Errorf("%+v != %+v", m.Medias[0], audio)
+	}
+	if video := m.Medias[1]; video.Description.Type != "video" || video.Title != "Camera" ||
+		video.Bandwidths.Value(BandwidthApplicationSpecific) != 128 || video.Connections[0].Host != "example.com" {
+		t.Errorf("unexpected video %+v", video)
+	}
+	t.Run("RoundTrip", func(t *testing.T) {
+		b, err := m.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := Decode(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var u testSession
+		if err = Unmarshal(decoded, &u); err != nil {
+			t.Fatal(err)
+		}
+		v.Skipped = ""
+		v.Connection.NetworkType, v.Connection.AddressType = "IN", "IP4"
+		if !u.Connection.Equal(v.Connection) {
+			t.Errorf("%+v != %+v", u.Connection, v.Connection)
+		}
+		u.Connection = v.Connection
+		if !reflect.DeepEqual(u, v) {
+			t.Errorf("%+v != %+v", u, v)
+		}
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+	m, err := Decode(loadData(t, "spd_session_ex_webrtc1", testNL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v struct {
+		Medias []struct {
+			Type       string      `sdp:"m.type"`
+			Port       uint16      `sdp:"m.port"`
+			MID        string      `sdp:"a=mid"`
+			RTCPMux    bool        `sdp:"a=rtcp-mux"`
+			RTPMap     RTPMap      `sdp:"a=rtpmap"`
+			Candidates []Candidate `sdp:"a=candidate,multi"`
+			Missing    int         `sdp:"a=ptime"`
+		} `sdp:"m"`
+	}
+	if err = Unmarshal(m, &v); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.Medias) != 1 {
+		t.Fatal("unexpected medias count", len(v.Medias))
+	}
+	media := v.Medias[0]
+	if media.Type != "application" || media.Port != 9 || media.RTPMap.Encoding != "google-data" {
+		t.Errorf("unexpected media %+v", media)
+	}
+	if len(media.Candidates) != 6 || media.Candidates[0].Port != 56024 {
+		t.Errorf("unexpected candidates %+v", media.Candidates)
+	}
+	t.Run("Flag", func(t *testing.T) {
+		var v struct {
+			Medias []struct {
+				RTCPMux  bool `sdp:"a=rtcp-mux"`
+				RecvOnly bool `sdp:"a=recvonly"`
+			} `sdp:"m"`
+			RecvOnly bool `sdp:"a=recvonly"`
+		}
+		v.RecvOnly = true
+		if err := Unmarshal(m, &v); err != nil {
+			t.Fatal(err)
+		}
+		if !v.RecvOnly {
+			t.Error("missing flag should not change field")
+		}
+		if len(v.Medias) != 1 || !v.Medias[0].RTCPMux || v.Medias[0].RecvOnly {
+			t.Errorf("unexpected medias %+v", v.Medias)
+		}
+	})
+	t.Run("Pointer", func(t *testing.T) {
+		type pointerMedia struct {
+			RTPMap  *RTPMap      `sdp:"a=rtpmap"`
+			MID     *string      `sdp:"a=mid"`
+			Missing *int         `sdp:"a=ptime"`
+			RTPMaps []*RTPMap    `sdp:"a=rtpmap,multi"`
+			Cands   []*Candidate `sdp:"a=candidate,multi"`
+		}
+		var v struct {
+			Medias []pointerMedia `sdp:"m"`
+		}
+		if err := Unmarshal(m, &v); err != nil {
+			t.Fatal(err)
+		}
+		media := v.Medias[0]
+		if media.RTPMap == nil || media.RTPMap.Encoding != "google-data" {
+			t.Errorf("unexpected rtpmap %+v", media.RTPMap)
+		}
+		if media.MID == nil || *media.MID != "data" || media.Missing != nil {
+			t.Errorf("unexpected mid %v or ptime %v", media.MID, media.Missing)
+		}
+		if len(media.RTPMaps) != 1 || len(media.Cands) != 6 || media.Cands[0].Port != 56024 {
+			t.Errorf("unexpected values %+v", media)
+		}
+		marshaled, err := Marshal(&struct {
+			Media *pointerMedia `sdp:"m=application"`
+		}{Media: &v.Medias[0]})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a := marshaled.Medias[0].Attributes; a.Value("rtpmap") != "127 google-data/90000" || a.Value("mid") != "data" {
+			t.Errorf("unexpected attributes %v", a)
+		}
+	})
+	t.Run("Copy", func(t *testing.T) {
+		data := loadData(t, "spd_session_ex_webrtc1", testNL)
+		s, err := DecodeSession(data, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded := new(Message)
+		d := NewDecoderWithOptions(s, DecoderOptions{ZeroCopy: true})
+		if err = d.Decode(decoded); err != nil {
+			t.Fatal(err)
+		}
+		var v struct {
+			Medias []struct {
+				MID    string `sdp:"a=mid"`
+				RTPMap RTPMap `sdp:"a=rtpmap"`
+			} `sdp:"m"`
+		}
+		if err = Unmarshal(decoded, &v); err != nil {
+			t.Fatal(err)
+		}
+		for i := range data {
+			data[i] = 'x'
+		}
+		for i := range s {
+			for j := range s[i].Value {
+				s[i].Value[j] = 'x'
+			}
+		}
+		if v.Medias[0].MID != "data" || v.Medias[0].RTPMap.Encoding != "google-data" {
+			t.Errorf("unmarshaled values reference decoded message: %+v", v.Medias[0])
+		}
+	})
+	t.Run("NoMatch", func(t *testing.T) {
+		var v struct {
+			Video  *testStream  `sdp:"m=video"`
+			Audios []testStream `sdp:"m=audio"`
+		}
+		if err := Unmarshal(m, &v); err != nil {
+			t.Fatal(err)
+		}
+		if v.Video != nil || len(v.Audios) != 0 {
+			t.Error("unexpected medias")
+		}
+	})
+}
+
+func TestMarshal_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		v    interface{}
+		err  error
+	}{
+		{"NotPointer", testSession{}, ErrUnsupportedType},
+		{"NotStruct", new(int), ErrUnsupportedType},
+		{"UnknownTag", &struct {
+			V string `sdp:"x"`
+		}{"v"}, ErrInvalidTag},
+		{"UnknownOption", &struct {
+			V string `sdp:"a=tool,omitempty"`
+		}{"v"}, ErrInvalidTag},
+		{"MediaLevel", &struct {
+			V int `sdp:"m.port"`
+		}{1}, ErrInvalidTag},
+		{"MultiNotSlice", &struct {
+			V string `sdp:"a=tool,multi"`
+		}{"v"}, ErrUnsupportedType},
+		{"NoCodec", &struct {
+			V struct{ X int } `sdp:"a=x-unknown"`
+		}{struct{ X int }{1}}, ErrUnknownAttribute},
+		{"Type", &struct {
+			V int `sdp:"s"`
+		}{1}, ErrUnsupportedType},
+		{"ByteString", &struct {
+			V string `sdp:"a=tool"`
+		}{"a\nb"}, ErrInvalidByteString},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Marshal(tc.v); !errors.Is(err, tc.err) {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+	t.Run("Unmarshal", func(t *testing.T) {
+		m := &Message{Attributes: Attributes{{Key: "ptime", Value: "x"}}}
+		var v struct {
+			PTime int `sdp:"a=ptime"`
+		}
+		if err := Unmarshal(m, &v); !errors.Is(err, ErrInvalidNumber) {
+			t.Errorf("unexpected error %v", err)
+		}
+		if err := Unmarshal(m, v); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+}
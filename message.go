@@ -1,6 +1,7 @@
 package sdp
 
 import (
+	"fmt"
 	"strings"
 	"time"
 )
@@ -51,20 +52,64 @@ type Message struct {
 	Origin        Origin
 	Name          string
 	Info          string
-	Email         string
-	Phone         string
+	Emails        []Address
+	Phones        []Address
 	URI           string
 	Connection    ConnectionData
 	Attributes    Attributes
 	Medias        Medias
 	Encryption    Encryption
-	Bandwidths    map[BandwidthType]int
-	BandwidthType BandwidthType
+	Bandwidths    Bandwidths
 	Timing        []Timing
-	TZAdjustments []TimeZone
+	TZAdjustments []TimeZone // single "z=" field that follows time descriptions
+	Raw           RawSection // lines before first media description
+}
+
+// Reset resets message to blank state, keeping memory of slices, so
+// decoding to m can be done without allocations. See
+// DecoderOptions.ZeroCopy.
+//
+// Previous slices and IPs of m must not be used after Reset, because
+// their memory is overwritten by decoding.
+func (m *Message) Reset() {
+	*m = Message{
+		Connection:    ConnectionData{IP: m.Connection.IP[:0]},
+		Emails:        m.Emails[:0],
+		Phones:        m.Phones[:0],
+		Attributes:    m.Attributes[:0],
+		Medias:        m.Medias[:0],
+		Bandwidths:    m.Bandwidths[:0],
+		Timing:        m.Timing[:0],
+		TZAdjustments: m.TZAdjustments[:0],
+		Raw:           RawSection{Lines: m.Raw.Lines[:0]},
+	}
+}
+
+// RawSection holds original lines of message or media section that
+// are captured by Decoder in lossless mode, including lines of unknown
+// types. See DecoderOptions.Lossless.
+type RawSection struct {
+	Lines Session
+	// canonical is section encoded right after decoding, that is used
+	// to determine whether section was modified.
+	canonical Session
+}
+
+// String returns original lines with encryption keys redacted.
+func (r RawSection) String() string {
+	return fmt.Sprint(r.Lines)
+}
+
+// GoString implements fmt.GoStringer, redacting encryption keys.
+func (r RawSection) GoString() string {
+	return fmt.Sprintf("sdp.RawSection{Lines:%#v}", r.Lines)
 }
 
 // Timing wraps "repeat times" and "timing" information.
+//
+// Time description with several "r=" fields is decoded as consecutive
+// Timing values with same Start and End, one per "r=" field, and such
+// values are encoded back as single time description.
 type Timing struct {
 	Start   time.Time
 	End     time.Time
@@ -73,6 +118,13 @@ type Timing struct {
 	Offsets []time.Duration
 }
 
+// sameRepeatedTime reports whether t is another "r=" field of time
+// description of previous Timing p.
+func (t Timing) sameRepeatedTime(p Timing) bool {
+	return len(t.Offsets) > 0 && len(p.Offsets) > 0 &&
+		t.Start.Equal(p.Start) && t.End.Equal(p.End)
+}
+
 // Start returns start of session.
 func (m *Message) Start() time.Time {
 	if len(m.Timing) == 0 {
@@ -134,17 +186,40 @@ func (e Encryption) Equal(b Encryption) bool {
 	return e == b
 }
 
-// Bandwidths is map of BandwidthsType and int (bytes per second).
-type Bandwidths map[BandwidthType]int
-
 // Media is media description and attributes.
 type Media struct {
 	Title       string
 	Description MediaDescription
-	Connection  ConnectionData
+	Connections []ConnectionData // zero or more, e.g. for layered multicast
 	Attributes  Attributes
 	Encryption  Encryption
 	Bandwidths  Bandwidths
+	Raw         RawSection
+}
+
+// reset is Message.Reset for media.
+func (m *Media) reset() {
+	*m = Media{
+		Description: MediaDescription{Formats: m.Description.Formats[:0]},
+		Connections: m.Connections[:0],
+		Attributes:  m.Attributes[:0],
+		Bandwidths:  m.Bandwidths[:0],
+		Raw:         RawSection{Lines: m.Raw.Lines[:0]},
+	}
+}
+
+// addConnection appends blank connection data to m and returns it,
+// reusing memory of connection data that is left by reset.
+func (m *Media) addConnection() *ConnectionData {
+	n := len(m.Connections)
+	if cap(m.Connections) > n {
+		m.Connections = m.Connections[:n+1]
+		c := &m.Connections[n]
+		*c = ConnectionData{IP: c.IP[:0]}
+		return c
+	}
+	m.Connections = append(m.Connections, ConnectionData{})
+	return &m.Connections[n]
 }
 
 // PayloadFormat returns payload format from a=rtpmap.
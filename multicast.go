@@ -0,0 +1,182 @@
+package sdp
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Bounds of ConnectionData TTL and Addresses that are checked by
+// Decoder and Message.Validate.
+const (
+	MaxTTL       = 255
+	MaxAddresses = 1 << 16
+)
+
+// MulticastScope is scope of multicast address.
+type MulticastScope int
+
+// Multicast scopes of RFC 4291 Section 2.7 for IP6 and of
+// RFC 2365 Section 6 for IP4, where IP4 scopes are approximated
+// by address ranges.
+const (
+	ScopeNone              MulticastScope = iota // not multicast
+	ScopeInterfaceLocal                          // IP6 ff01::/16
+	ScopeLinkLocal                               // IP6 ff02::/16, IP4 224.0.0.0/24
+	ScopeAdminLocal                              // IP6 ff04::/16, IP4 239.0.0.0/8
+	ScopeSiteLocal                               // IP6 ff05::/16, IP4 239.255.0.0/16
+	ScopeOrganizationLocal                       // IP6 ff08::/16, IP4 239.192.0.0/14
+	ScopeGlobal                                  // IP6 ff0e::/16, other IP4 multicast
+	ScopeOther                                   // unassigned or reserved IP6 scope
+)
+
+var scopeToStr = map[MulticastScope]string{
+	ScopeNone:              "none",
+	ScopeInterfaceLocal:    "interface-local",
+	ScopeLinkLocal:         "link-local",
+	ScopeAdminLocal:        "admin-local",
+	ScopeSiteLocal:         "site-local",
+	ScopeOrganizationLocal: "organization-local",
+	ScopeGlobal:            "global",
+	ScopeOther:             "other",
+}
+
+func (s MulticastScope) String() string {
+	if v, ok := scopeToStr[s]; ok {
+		return v
+	}
+	return "scope " + strconv.Itoa(int(s))
+}
+
+// Multicast returns true if IP of c is multicast address.
+func (c ConnectionData) Multicast() bool {
+	return c.IP.IsMulticast()
+}
+
+// Scope returns multicast scope of c.IP or ScopeNone if it is not
+// multicast address.
+func (c ConnectionData) Scope() MulticastScope {
+	if !c.IP.IsMulticast() {
+		return ScopeNone
+	}
+	if ip := c.IP.To4(); ip != nil {
+		switch {
+		case ip[0] == 224 && ip[1] == 0 && ip[2] == 0:
+			return ScopeLinkLocal
+		case ip[0] == 239 && ip[1] == 255:
+			return ScopeSiteLocal
+		case ip[0] == 239 && ip[1]&0xfc == 192:
+			return ScopeOrganizationLocal
+		case ip[0] == 239:
+			return ScopeAdminLocal
+		default:
+			return ScopeGlobal
+		}
+	}
+	switch c.IP[1] & 0x0f {
+	case 0x1:
+		return ScopeInterfaceLocal
+	case 0x2:
+		return ScopeLinkLocal
+	case 0x4:
+		return ScopeAdminLocal
+	case 0x5:
+		return ScopeSiteLocal
+	case 0x8:
+		return ScopeOrganizationLocal
+	case 0xe:
+		return ScopeGlobal
+	default:
+		return ScopeOther
+	}
+}
+
+// Expand returns addresses that are implied by <number of addresses>
+// of multicast connection data, e.g. 224.2.1.1, 224.2.1.2 and 224.2.1.3
+// for "224.2.1.1/127/3" or FF15::101, FF15::102 and FF15::103 for
+// "FF15::101/3", see RFC 4566 Section 5.7.
+//
+// Only c.IP is returned if Addresses is zero, and nil is returned if
+// IP is blank, e.g. for FQDN.
+func (c ConnectionData) Expand() []net.IP {
+	if len(c.IP) == 0 {
+		return nil
+	}
+	n := c.Addresses
+	if n == 0 {
+		n = 1
+	}
+	base := c.IP.To4()
+	if base == nil {
+		base = c.IP.To16()
+	}
+	ips := make([]net.IP, n)
+	for i := range ips {
+		ip := make(net.IP, len(base))
+		copy(ip, base)
+		addIP(ip, i)
+		ips[i] = ip
+	}
+	return ips
+}
+
+// addIP adds n to ip in place.
+func addIP(ip net.IP, n int) {
+	for i := len(ip) - 1; i >= 0 && n > 0; i-- {
+		n += int(ip[i])
+		ip[i] = byte(n)
+		n >>= 8
+	}
+}
+
+// ErrPortsMismatch means that number of addresses of connection data
+// does not match number of ports of media description.
+var ErrPortsMismatch = errors.New("number of addresses and ports mismatch")
+
+// Layers returns transport addresses of layered encoding of media,
+// pairing expanded addresses of media connections with ports of media
+// description, see RFC 4566 Section 5.14. For example, media
+// "m=video 49170/2 RTP/AVP 31" with "c=IN IP4 224.2.1.1/127/2" has
+// 224.2.1.1:49170 and 224.2.1.2:49172 layers.
+//
+// Ports are incremented by two for RTP, where odd ports are used by
+// RTCP. Single address or port is paired with all ports or addresses.
+func (m *Media) Layers() ([]*net.UDPAddr, error) {
+	var ips []net.IP
+	for _, c := range m.Connections {
+		ips = append(ips, c.Expand()...)
+	}
+	if len(ips) == 0 {
+		return nil, errors.Wrap(ErrNoAddress, "no connection data with IP")
+	}
+	ports := m.Description.PortsNumber
+	if ports == 0 {
+		ports = 1
+	}
+	n := len(ips)
+	switch {
+	case ports == n, ports == 1:
+	case n == 1:
+		n = ports
+	default:
+		return nil, errors.Wrapf(ErrPortsMismatch, "%d addresses and %d ports", n, ports)
+	}
+	step := 1
+	if strings.Contains(m.Description.Protocol, "RTP/") {
+		step = 2
+	}
+	layers := make([]*net.UDPAddr, n)
+	for i := range layers {
+		a := &net.UDPAddr{IP: ips[0], Port: m.Description.Port}
+		if len(ips) > 1 {
+			a.IP = ips[i]
+		}
+		if ports > 1 {
+			a.Port += i * step
+		}
+		layers[i] = a
+	}
+	return layers, nil
+}
@@ -0,0 +1,202 @@
+package sdp
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestConnectionData_Expand(t *testing.T) {
+	for _, tc := range []struct {
+		c   ConnectionData
+		ips []string
+	}{
+		{
+			c:   ConnectionData{IP: net.ParseIP("224.2.1.1"), TTL: 127, Addresses: 3},
+			ips: []string{"224.2.1.1", "224.2.1.2", "224.2.1.3"},
+		},
+		{
+			c:   ConnectionData{IP: net.ParseIP("224.2.1.255"), TTL: 127, Addresses: 2},
+			ips: []string{"224.2.1.255", "224.2.2.0"},
+		},
+		{
+			c:   ConnectionData{IP: net.ParseIP("FF15::101"), Addresses: 3},
+			ips: []string{"ff15::101", "ff15::102", "ff15::103"},
+		},
+		{
+			c:   ConnectionData{IP: net.ParseIP("10.0.0.1")},
+			ips: []string{"10.0.0.1"},
+		},
+		{
+			c: ConnectionData{Host: "media.example.com"},
+		},
+	} {
+		t.Run(tc.c.ConnectionAddress(), func(t *testing.T) {
+			ips := tc.c.Expand()
+			if len(ips) != len(tc.ips) {
+				t.Fatalf("unexpected %v", ips)
+			}
+			for i := range ips {
+				if ips[i].String() != tc.ips[i] {
+					t.Errorf("%d: %s != %s", i, ips[i], tc.ips[i])
+				}
+			}
+		})
+	}
+	t.Run("NoAliasing", func(t *testing.T) {
+		c := ConnectionData{IP: net.ParseIP("224.2.1.1"), Addresses: 2}
+		c.Expand()[0][0] = 1
+		if !c.IP.Equal(net.ParseIP("224.2.1.1")) {
+			t.Error("IP changed")
+		}
+	})
+}
+
+func TestDecoder_MultipleConnections(t *testing.T) {
+	data := loadData(t, "sdp_session_ex_layered", testCRNL)
+	m, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Medias) != 2 {
+		t.Fatalf("unexpected medias %d", len(m.Medias))
+	}
+	if len(m.Medias[0].Connections) != 1 || len(m.Medias[1].Connections) != 2 {
+		t.Fatalf("unexpected connections %v, %v", m.Medias[0].Connections, m.Medias[1].Connections)
+	}
+	expected := []string{"224.2.1.1:49170", "224.2.1.2:49172"}
+	for _, media := range m.Medias {
+		layers, err := media.Layers()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(layers) != fmt.Sprint(expected) {
+			t.Errorf("%v != %v", layers, expected)
+		}
+	}
+	t.Run("Encode", func(t *testing.T) {
+		out := m.Append(nil).AppendTo(nil)
+		if !bytes.Equal(bytes.TrimSpace(out), bytes.TrimSpace(data)) {
+			t.Errorf("%s != %s", out, data)
+		}
+	})
+	t.Run("Reuse", func(t *testing.T) {
+		s, err := DecodeSession(data, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := NewDecoder(s)
+		m := new(Message)
+		if err = d.Decode(m); err != nil {
+			t.Fatal(err)
+		}
+		m.Reset()
+		d.Reset(s)
+		if err = d.Decode(m); err != nil {
+			t.Fatal(err)
+		}
+		if len(m.Medias[1].Connections) != 2 || !m.Medias[1].Connections[1].IP.Equal(net.ParseIP("224.2.1.2")) {
+			t.Errorf("unexpected connections %v", m.Medias[1].Connections)
+		}
+	})
+}
+
+func TestMedia_Layers(t *testing.T) {
+	multicast := ConnectionData{IP: net.ParseIP("224.2.1.1"), TTL: 127, Addresses: 2}
+	for _, tc := range []struct {
+		name   string
+		m      Media
+		layers string
+		err    error
+	}{
+		{
+			name: "Single",
+			m: Media{
+				Description: MediaDescription{Port: 49170, Protocol: "RTP/AVP"},
+				Connections: []ConnectionData{{IP: net.ParseIP("10.0.0.1")}},
+			},
+			layers: "[10.0.0.1:49170]",
+		},
+		{
+			name: "Ports",
+			m: Media{
+				Description: MediaDescription{Port: 49170, PortsNumber: 3, Protocol: "udp"},
+				Connections: []ConnectionData{{IP: net.ParseIP("10.0.0.1")}},
+			},
+			layers: "[10.0.0.1:49170 10.0.0.1:49171 10.0.0.1:49172]",
+		},
+		{
+			name: "Addresses",
+			m: Media{
+				Description: MediaDescription{Port: 49170, Protocol: "RTP/AVP"},
+				Connections: []ConnectionData{multicast},
+			},
+			layers: "[224.2.1.1:49170 224.2.1.2:49170]",
+		},
+		{
+			name: "Mismatch",
+			m: Media{
+				Description: MediaDescription{Port: 49170, PortsNumber: 3, Protocol: "RTP/AVP"},
+				Connections: []ConnectionData{multicast},
+			},
+			err: ErrPortsMismatch,
+		},
+		{
+			name: "NoConnection",
+			m:    Media{Description: MediaDescription{Port: 49170, Protocol: "RTP/AVP"}},
+			err:  ErrNoAddress,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			layers, err := tc.m.Layers()
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("unexpected error %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(layers) != tc.layers {
+				t.Errorf("%v != %s", layers, tc.layers)
+			}
+		})
+	}
+}
+
+func TestConnectionData_Scope(t *testing.T) {
+	for _, tc := range []struct {
+		ip    string
+		scope MulticastScope
+	}{
+		{"10.0.0.1", ScopeNone},
+		{"224.0.0.251", ScopeLinkLocal},
+		{"224.2.1.1", ScopeGlobal},
+		{"239.1.1.1", ScopeAdminLocal},
+		{"239.192.0.1", ScopeOrganizationLocal},
+		{"239.255.255.250", ScopeSiteLocal},
+		{"ff01::1", ScopeInterfaceLocal},
+		{"ff02::fb", ScopeLinkLocal},
+		{"ff04::1", ScopeAdminLocal},
+		{"ff08::1", ScopeOrganizationLocal},
+		{"ff03::1", ScopeOther},
+		{"2001:db8::1", ScopeNone},
+	} {
+		t.Run(tc.ip, func(t *testing.T) {
+			c := ConnectionData{IP: net.ParseIP(tc.ip)}
+			if c.Scope() != tc.scope {
+				t.Errorf("%s != %s", c.Scope(), tc.scope)
+			}
+			if c.Multicast() != (tc.scope != ScopeNone) {
+				t.Error("unexpected Multicast")
+			}
+		})
+	}
+	if MulticastScope(100).String() != "scope 100" {
+		t.Error("unexpected String")
+	}
+}
@@ -0,0 +1,215 @@
+package sdp
+
+import (
+	"encoding/hex"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Network types (<nettype>) with built-in NetworkType implementations.
+const (
+	NetworkTypeInternet = "IN"
+	NetworkTypePSTN     = "PSTN" // RFC 7195
+	NetworkTypeATM      = "ATM"  // RFC 3108
+)
+
+// Address types (<addrtype>) of PSTN and ATM network types.
+const (
+	AddressTypeE164  = "E164"
+	AddressTypeNSAP  = "NSAP"
+	AddressTypeGWID  = "GWID"
+	AddressTypeAlias = "ALIAS"
+	// AddressTypeUnknown is "-" address type, which is used with "-"
+	// address if address is not known.
+	AddressTypeUnknown = "-"
+)
+
+// NetworkType decodes <connection-address> of <nettype>. Decoder uses
+// NetworkType that is registered by RegisterNetworkType for network
+// types other than IN.
+type NetworkType interface {
+	// DecodeAddress decodes <connection-address> v to c, where
+	// c.NetworkType and c.AddressType are already decoded. Decoded
+	// values must not reference memory of v.
+	DecodeAddress(c *ConnectionData, v []byte) error
+}
+
+// NetworkAddress is <connection-address> of network type other than IN,
+// that is stored in ConnectionData.Address. ConnectionData.Equal
+// compares addresses by AppendTo result.
+type NetworkAddress interface {
+	// AppendTo appends <connection-address> to b.
+	AppendTo(b []byte) []byte
+}
+
+// NetworkAddressCloner is implemented by NetworkAddress that references
+// mutable memory, like slice or pointer, so Message.Clone can copy it.
+// Other third-party addresses are copied shallowly by Message.Clone.
+type NetworkAddressCloner interface {
+	NetworkAddress
+	// CloneAddress returns copy that does not share memory with
+	// address.
+	CloneAddress() NetworkAddress
+}
+
+var (
+	networkTypesMux sync.RWMutex
+	networkTypes    = map[string]NetworkType{
+		NetworkTypeInternet: InternetNetwork{},
+		NetworkTypePSTN:     PSTNNetwork{},
+		NetworkTypeATM:      ATMNetwork{},
+	}
+)
+
+// RegisterNetworkType registers t as NetworkType for <nettype> name,
+// replacing previously registered one.
+//
+// Note that IN network type is always decoded by Decoder directly
+// to ConnectionData IP or Host.
+func RegisterNetworkType(name string, t NetworkType) {
+	networkTypesMux.Lock()
+	networkTypes[name] = t
+	networkTypesMux.Unlock()
+}
+
+// LookupNetworkType returns NetworkType that is registered for
+// <nettype> name.
+func LookupNetworkType(name string) (NetworkType, bool) {
+	networkTypesMux.RLock()
+	t, ok := networkTypes[name]
+	networkTypesMux.RUnlock()
+	return t, ok
+}
+
+// ErrInvalidAddress means that <connection-address> is not valid for
+// <nettype> and <addrtype>.
+var ErrInvalidAddress = errors.New("invalid connection address")
+
+func invalidAddress(c *ConnectionData, v []byte) error {
+	return errors.Wrapf(ErrInvalidAddress, "%s %s %q",
+		c.NetworkType, c.AddressType, v,
+	)
+}
+
+// InternetNetwork is NetworkType of IN <nettype>, that decodes
+// IP4 and IP6 addresses to IP or FQDN to Host.
+type InternetNetwork struct{}
+
+// DecodeAddress implements NetworkType.
+func (InternetNetwork) DecodeAddress(c *ConnectionData, v []byte) error {
+	host, err := decodeInternetAddress(c, v)
+	if err != nil {
+		return err
+	}
+	c.Host = string(host)
+	return nil
+}
+
+// RawAddress is <connection-address> of unknown <nettype>, that is
+// decoded as is if DecoderOptions.Strict is not set.
+type RawAddress string
+
+// AppendTo implements NetworkAddress.
+func (a RawAddress) AppendTo(b []byte) []byte {
+	return append(b, a...)
+}
+
+// PSTNAddress is <connection-address> of PSTN <nettype>, that is E.164
+// phone number like "+15551234567" or "-" if not known, as defined in
+// RFC 7195 Section 4.
+type PSTNAddress string
+
+// AppendTo implements NetworkAddress.
+func (a PSTNAddress) AppendTo(b []byte) []byte {
+	return append(b, a...)
+}
+
+// PSTNNetwork is NetworkType of PSTN <nettype>, that decodes
+// PSTNAddress for E164 or "-" <addrtype>.
+type PSTNNetwork struct{}
+
+// DecodeAddress implements NetworkType.
+func (PSTNNetwork) DecodeAddress(c *ConnectionData, v []byte) error {
+	switch c.AddressType {
+	case AddressTypeE164:
+		if n, ok := NormalizeE164(string(v)); !ok || n != string(v) {
+			return invalidAddress(c, v)
+		}
+	case AddressTypeUnknown:
+		if string(v) != AddressTypeUnknown {
+			return invalidAddress(c, v)
+		}
+	default:
+		return invalidAddress(c, v)
+	}
+	c.Address = PSTNAddress(v)
+	return nil
+}
+
+// ATMAddress is <connection-address> of ATM <nettype>, that is NSAP,
+// E.164, gateway identifier or alias address, or "-" if not known,
+// as defined in RFC 3108 Section 5.3.
+type ATMAddress string
+
+// AppendTo implements NetworkAddress.
+func (a ATMAddress) AppendTo(b []byte) []byte {
+	return append(b, a...)
+}
+
+// nsapLen is length of NSAP address in bytes.
+const nsapLen = 20
+
+// NSAP returns 20-byte NSAP address that is encoded as hex digits with
+// optional dots, like "47.0091.8100.0000.0060.3E64.FD01.0060.3E64.FD01.00",
+// or false if a is not valid NSAP address.
+func (a ATMAddress) NSAP() ([]byte, bool) {
+	digits := make([]byte, 0, 2*nsapLen)
+	for i := 0; i < len(a); i++ {
+		if a[i] != '.' {
+			digits = append(digits, a[i])
+		}
+	}
+	if len(digits) != 2*nsapLen {
+		return nil, false
+	}
+	b := make([]byte, nsapLen)
+	if _, err := hex.Decode(b, digits); err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+// maxATMNameLen is maximum length of GWID and ALIAS ATM addresses.
+const maxATMNameLen = 32
+
+// ATMNetwork is NetworkType of ATM <nettype>, that decodes ATMAddress
+// for NSAP, E164, GWID, ALIAS and "-" <addrtype>.
+type ATMNetwork struct{}
+
+// DecodeAddress implements NetworkType.
+func (ATMNetwork) DecodeAddress(c *ConnectionData, v []byte) error {
+	a := ATMAddress(v)
+	var ok bool
+	switch c.AddressType {
+	case AddressTypeNSAP:
+		_, ok = a.NSAP()
+	case AddressTypeE164:
+		// E.164 numbers in ATM are decimal digits without "+".
+		ok = len(v) > 0 && len(v) <= maxE164Digits
+		for _, b := range v {
+			ok = ok && b >= '0' && b <= '9'
+		}
+	case AddressTypeGWID, AddressTypeAlias:
+		ok = len(v) > 0 && len(v) <= maxATMNameLen
+	}
+	if string(v) == AddressTypeUnknown {
+		// Any address can be "-" in ATM.
+		ok = true
+	}
+	if !ok {
+		return invalidAddress(c, v)
+	}
+	c.Address = a
+	return nil
+}
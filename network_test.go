@@ -0,0 +1,216 @@
+package sdp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestDecoder_NetworkTypes(t *testing.T) {
+	data := loadData(t, "sdp_session_ex_nettypes", testCRNL)
+	m, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, tc := range []struct {
+		c       ConnectionData
+		address NetworkAddress
+	}{
+		{m.Connection, PSTNAddress("+15551234567")},
+		{m.Medias[0].Connections[0], PSTNAddress("-")},
+		{m.Medias[1].Connections[0], ATMAddress("47.0091.8100.0000.0060.3E64.FD01.0060.3E64.FD01.00")},
+		{m.Medias[2].Connections[0], ATMAddress("9178294700")},
+		{m.Medias[3].Connections[0], RawAddress("some-address")},
+	} {
+		if tc.c.Address != tc.address {
+			t.Errorf("%d: %#v != %#v", i, tc.c.Address, tc.address)
+		}
+		if len(tc.c.IP) != 0 {
+			t.Errorf("%d: unexpected IP", i)
+		}
+	}
+	t.Run("Encode", func(t *testing.T) {
+		out := m.Append(nil).AppendTo(nil)
+		if !bytes.Equal(bytes.TrimSpace(out), bytes.TrimSpace(data)) {
+			t.Errorf("%s != %s", out, data)
+		}
+	})
+	t.Run("Strict", func(t *testing.T) {
+		s, err := DecodeSession(data, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := NewDecoderWithOptions(s, DecoderOptions{Strict: true})
+		err = d.Decode(new(Message))
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if fieldErr.Line != 13 || fieldErr.Type != TypeConnectionData {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+	t.Run("Clone", func(t *testing.T) {
+		if !m.Clone().Equal(m) {
+			t.Error("clone should be equal")
+		}
+	})
+}
+
+func TestNetworkType_DecodeAddress(t *testing.T) {
+	for _, tc := range []struct {
+		network  string
+		addrType string
+		address  string
+		ok       bool
+	}{
+		{NetworkTypePSTN, AddressTypeE164, "+15551234567", true},
+		{NetworkTypePSTN, AddressTypeE164, "+1 555 123 4567", false},
+		{NetworkTypePSTN, AddressTypeE164, "15551234567", false},
+		{NetworkTypePSTN, AddressTypeUnknown, "-", true},
+		{NetworkTypePSTN, AddressTypeUnknown, "+15551234567", false},
+		{NetworkTypePSTN, "IP4", "10.0.0.1", false},
+		{NetworkTypeATM, AddressTypeNSAP, "47.0091.8100.0000.0060.3E64.FD01.0060.3E64.FD01.00", true},
+		{NetworkTypeATM, AddressTypeNSAP, "47.0091", false},
+		{NetworkTypeATM, AddressTypeNSAP, "-", true},
+		{NetworkTypeATM, AddressTypeE164, "9178294700", true},
+		{NetworkTypeATM, AddressTypeE164, "+9178294700", false},
+		{NetworkTypeATM, AddressTypeGWID, "officeABC", true},
+		{NetworkTypeATM, AddressTypeAlias, "a123456789012345678901234567890123", false},
+		{NetworkTypeATM, AddressTypeUnknown, "-", true},
+		{NetworkTypeATM, "IP4", "10.0.0.1", false},
+		{NetworkTypeInternet, "IP4", "10.0.0.1", true},
+		{NetworkTypeInternet, "IP4", "media.example.com", true},
+		{NetworkTypeInternet, "IP4", "10.0.0.256", false},
+	} {
+		t.Run(tc.network+" "+tc.addrType+" "+tc.address, func(t *testing.T) {
+			n, ok := LookupNetworkType(tc.network)
+			if !ok {
+				t.Fatal("not registered")
+			}
+			c := ConnectionData{NetworkType: tc.network, AddressType: tc.addrType}
+			err := n.DecodeAddress(&c, []byte(tc.address))
+			if (err == nil) != tc.ok {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err != nil && tc.network != NetworkTypeInternet && !errors.Is(err, ErrInvalidAddress) {
+				t.Errorf("unexpected error %v", err)
+			}
+			if err == nil && c.ConnectionAddress() != tc.address {
+				t.Errorf("%q != %q", c.ConnectionAddress(), tc.address)
+			}
+		})
+	}
+}
+
+func TestATMAddress_NSAP(t *testing.T) {
+	b, ok := ATMAddress("47.0091.8100.0000.0060.3E64.FD01.0060.3E64.FD01.00").NSAP()
+	if !ok || len(b) != 20 || b[0] != 0x47 || b[19] != 0 {
+		t.Errorf("unexpected %x", b)
+	}
+	if _, ok := ATMAddress("47.0091.8100.0000.0060.3E64.FD01.0060.3E64.FD01.XX").NSAP(); ok {
+		t.Error("should fail")
+	}
+}
+
+type testNetworkAddress string
+
+func (a testNetworkAddress) AppendTo(b []byte) []byte {
+	return append(append(b, "test:"...), a...)
+}
+
+// testSliceAddress is not comparable with ==.
+type testSliceAddress []string
+
+func (a testSliceAddress) AppendTo(b []byte) []byte {
+	return append(b, strings.Join(a, ".")...)
+}
+
+func (a testSliceAddress) CloneAddress() NetworkAddress {
+	return append(testSliceAddress(nil), a...)
+}
+
+func TestMessage_CloneNetworkAddress(t *testing.T) {
+	address := testSliceAddress{"a", "b"}
+	m := &Message{Connection: ConnectionData{NetworkType: "TEST", Address: address}}
+	c := m.Clone()
+	address[1] = "c"
+	if v := string(c.Connection.Address.AppendTo(nil)); v != "a.b" {
+		t.Errorf("clone references original address: %q", v)
+	}
+}
+
+func TestConnectionData_EqualAddress(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		a, b  NetworkAddress
+		equal bool
+	}{
+		{"Nil", nil, nil, true},
+		{"NilAndSlice", nil, testSliceAddress{"a"}, false},
+		{"SliceAndNil", testSliceAddress{"a"}, nil, false},
+		{"Slice", testSliceAddress{"a", "b"}, testSliceAddress{"a", "b"}, true},
+		{"SliceDifferent", testSliceAddress{"a", "b"}, testSliceAddress{"a", "c"}, false},
+		{"String", testNetworkAddress("a"), testNetworkAddress("a"), true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := ConnectionData{NetworkType: "TEST", Address: tc.a}
+			b := ConnectionData{NetworkType: "TEST", Address: tc.b}
+			if v := a.Equal(b); v != tc.equal {
+				t.Errorf("%v != %v", v, tc.equal)
+			}
+		})
+	}
+}
+
+type testNetwork struct{}
+
+func (testNetwork) DecodeAddress(c *ConnectionData, v []byte) error {
+	c.Address = testNetworkAddress(bytes.TrimPrefix(v, []byte("test:")))
+	return nil
+}
+
+func TestRegisterNetworkType(t *testing.T) {
+	RegisterNetworkType("TEST", testNetwork{})
+	defer func() {
+		networkTypesMux.Lock()
+		delete(networkTypes, "TEST")
+		networkTypesMux.Unlock()
+	}()
+	m := validMessage()
+	m.Connection = ConnectionData{
+		NetworkType: "TEST",
+		Address:     testNetworkAddress("addr"),
+	}
+	m.Timing = []Timing{{}}
+	s := m.Append(nil)
+	for _, l := range s {
+		if l.Type == TypeConnectionData && string(l.Value) != "TEST - test:addr" {
+			t.Fatalf("unexpected %s", l.Value)
+		}
+	}
+	d := NewDecoderWithOptions(s, DecoderOptions{Strict: true})
+	m = new(Message)
+	if err := d.Decode(m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Connection.Address != testNetworkAddress("addr") {
+		t.Errorf("unexpected %#v", m.Connection.Address)
+	}
+}
+
+func TestConnectionData_ValidateAddress(t *testing.T) {
+	m := validMessage()
+	m.Connection = ConnectionData{Address: PSTNAddress("+15551234567")}
+	var validationErr *ValidationError
+	if err := m.Validate(); !errors.As(err, &validationErr) || validationErr.Field != "Connection.NetworkType" {
+		t.Errorf("unexpected error %v", err)
+	}
+	m.Connection.NetworkType = NetworkTypePSTN
+	m.Connection.AddressType = AddressTypeE164
+	if err := m.Validate(); err != nil {
+		t.Error(err)
+	}
+}
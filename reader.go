@@ -0,0 +1,154 @@
+package sdp
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxSize is default limit for SDP body size in bytes that
+// is used by Reader.
+const DefaultMaxSize = 64 * 1024
+
+// ErrTooLarge means that SDP body exceeded maximum size.
+var ErrTooLarge = errors.New("sdp body is too large")
+
+// ReadError wraps error that stopped Reader and byte Offset of input
+// where it occurred.
+type ReadError struct {
+	Offset int64
+	Err    error
+}
+
+func (e ReadError) Error() string {
+	return fmt.Sprintf("ReadError at offset %d: %s", e.Offset, e.Err)
+}
+
+// Cause returns underlying error.
+func (e ReadError) Cause() error {
+	return e.Err
+}
+
+// Unwrap returns underlying error.
+func (e ReadError) Unwrap() error {
+	return e.Err
+}
+
+// Reader reads SDP from io.Reader, scanning lines incrementally.
+//
+// Blank lines and leading/trailing whitespace are ignored, just like
+// in DecodeSession.
+type Reader struct {
+	r       *bufio.Reader
+	maxSize int64
+	offset  int64
+	buf     []byte
+	lines   []int64 // offsets of lines from last ReadSession call
+	limits  Limits
+	s       Session
+}
+
+// NewReader returns Reader for r with DefaultMaxSize limit.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{
+		r:       bufio.NewReader(r),
+		maxSize: DefaultMaxSize,
+	}
+}
+
+// SetMaxSize sets maximum size of SDP body in bytes. Limit is disabled
+// if n is zero or negative.
+func (r *Reader) SetMaxSize(n int64) {
+	r.maxSize = n
+}
+
+// SetLimits sets limits for ReadSession and Decode. DefaultLimits
+// are used by default.
+func (r *Reader) SetLimits(l Limits) {
+	r.limits = l
+}
+
+// Offset returns count of bytes consumed from underlying io.Reader.
+func (r *Reader) Offset() int64 {
+	return r.offset
+}
+
+func (r *Reader) readLine() ([]byte, error) {
+	r.buf = r.buf[:0]
+	for {
+		chunk, err := r.r.ReadSlice(newLine)
+		r.buf = append(r.buf, chunk...)
+		r.offset += int64(len(chunk))
+		if r.maxSize > 0 && r.offset > r.maxSize {
+			return r.buf, ErrTooLarge
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		return r.buf, err
+	}
+}
+
+// ReadSession reads lines until EOF and appends them to s, returning
+// ReadError if any.
+//
+// If s is passed, it will be reused with its lines.
+func (r *Reader) ReadSession(s Session) (Session, error) {
+	r.lines = r.lines[:0]
+	l := r.limits.withDefaults()
+	for {
+		start := r.offset
+		b, err := r.readLine()
+		if err == ErrTooLarge {
+			return s, ReadError{Offset: r.maxSize, Err: err}
+		}
+		if err != nil && err != io.EOF {
+			return s, ReadError{Offset: start, Err: err}
+		}
+		if line := bytes.TrimSpace(b); len(line) > 0 {
+			// Offset of first non-whitespace character.
+			start += int64(bytes.Index(b, line))
+			decodeErr := firstError(
+				checkLimit("Lines", len(r.lines)+1, l.Lines),
+				checkLimit("LineLength", len(line), l.LineLength),
+			)
+			if decodeErr != nil {
+				return s, ReadError{Offset: start, Err: decodeErr}
+			}
+			if s, decodeErr = s.appendDecoded(line); decodeErr != nil {
+				return s, ReadError{Offset: start, Err: decodeErr}
+			}
+			r.lines = append(r.lines, start)
+		}
+		if err == io.EOF {
+			return s, nil
+		}
+	}
+}
+
+// Decode reads session until EOF and decodes it to m, returning
+// ReadError if any.
+func (r *Reader) Decode(m *Message) error {
+	var err error
+	// Reusing session is safe, because Decoder copies strings.
+	if r.s, err = r.ReadSession(r.s.reset()); err != nil {
+		return err
+	}
+	d := NewDecoderWithOptions(r.s, DecoderOptions{Limits: r.limits})
+	if err = d.Decode(m); err != nil {
+		return ReadError{Offset: r.lineOffset(d.pos - 1), Err: err}
+	}
+	return nil
+}
+
+// lineOffset returns offset of i-th line from last ReadSession call or
+// current offset if there is no such line.
+func (r *Reader) lineOffset(i int) int64 {
+	if i < 0 || i >= len(r.lines) {
+		return r.offset
+	}
+	return r.lines[i]
+}
@@ -0,0 +1,190 @@
+package sdp
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pkg/errors"
+)
+
+func TestReader_ReadSession(t *testing.T) {
+	for _, testEndLine := range testEndLines {
+		t.Run(testEndLine.name, func(t *testing.T) {
+			data := loadData(t, "sdp_session_ex_full", testEndLine.bytes)
+			expected, err := DecodeSession(data, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Reading byte-by-byte to check incremental scanning.
+			r := NewReader(iotest.OneByteReader(bytes.NewReader(data)))
+			s, err := r.ReadSession(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !s.Equal(expected) {
+				t.Error("sessions are not equal")
+			}
+			if r.Offset() != int64(len(data)) {
+				t.Errorf("offset %d != %d", r.Offset(), len(data))
+			}
+		})
+	}
+}
+
+func TestReader_Whitespace(t *testing.T) {
+	in := " a=12\n\tb=41231ar\r\n\n\n\tб=значение  "
+	r := NewReader(strings.NewReader(in))
+	s, err := r.ReadSession(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := DecodeSession([]byte(in), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Equal(expected) {
+		t.Errorf("%v != %v", s, expected)
+	}
+}
+
+func TestReader_LongLine(t *testing.T) {
+	value := strings.Repeat("x", 10*1024)
+	in := "v=0\r\na=" + value + "\r\n"
+	r := NewReader(strings.NewReader(in))
+	if _, err := r.ReadSession(nil); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	r = NewReader(strings.NewReader(in))
+	r.SetLimits(Limits{LineLength: -1})
+	s, err := r.ReadSession(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s) != 2 {
+		t.Fatalf("len(s) %d != 2", len(s))
+	}
+	if string(s[1].Value) != value {
+		t.Error("bad value")
+	}
+}
+
+func TestReader_Decode(t *testing.T) {
+	data := loadData(t, "sdp_session_ex_full", testCRNL)
+	m := new(Message)
+	if err := NewReader(bytes.NewReader(data)).Decode(m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "SDP Seminar" {
+		t.Errorf("unexpected name %q", m.Name)
+	}
+	if len(m.Medias) != 2 {
+		t.Errorf("len(medias) %d != 2", len(m.Medias))
+	}
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func TestReader_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		in     string
+		max    int64
+		offset int64
+		cause  error
+	}{
+		{
+			name:   "BadLine",
+			in:     "v=0\r\n  o\r\n",
+			offset: 7,
+		},
+		{
+			name:   "BadField",
+			in:     "v=0\no=jdoe 1 2 IN IP4 127.0.0.1\ns=-\nc=IN IP4 bad\n",
+			offset: 36,
+		},
+		{
+			name:   "NoOrigin",
+			in:     "v=0\ns=-\n",
+			offset: 4,
+		},
+		{
+			name:   "Blank",
+			in:     "",
+			offset: 0,
+		},
+		{
+			name:   "TooLarge",
+			in:     "v=0\ns=" + strings.Repeat("x", 128) + "\n",
+			max:    64,
+			offset: 64,
+			cause:  ErrTooLarge,
+		},
+		{
+			name:   "ReadFailed",
+			in:     "v=0\ns=-",
+			offset: 4,
+			cause:  iotest.ErrTimeout,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var src io.Reader = strings.NewReader(tc.in)
+			if tc.cause == iotest.ErrTimeout {
+				src = io.MultiReader(src, errReader{tc.cause})
+			}
+			r := NewReader(src)
+			if tc.max > 0 {
+				r.SetMaxSize(tc.max)
+			}
+			err := r.Decode(new(Message))
+			if err == nil {
+				t.Fatal("should fail")
+			}
+			readErr, ok := err.(ReadError)
+			if !ok {
+				t.Fatalf("unexpected error type %T", err)
+			}
+			if readErr.Offset != tc.offset {
+				t.Errorf("offset %d != %d (%s)", readErr.Offset, tc.offset, err)
+			}
+			if tc.cause != nil && errors.Cause(err) != tc.cause {
+				t.Errorf("unexpected cause %v", errors.Cause(err))
+			}
+		})
+	}
+}
+
+func TestReader_Unlimited(t *testing.T) {
+	in := "v=0\r\ni=" + strings.Repeat("x", DefaultMaxSize) + "\r\n"
+	r := NewReader(strings.NewReader(in))
+	if _, err := r.ReadSession(nil); errors.Cause(err) != ErrTooLarge {
+		t.Fatalf("unexpected error %v", err)
+	}
+	r = NewReader(strings.NewReader(in))
+	r.SetMaxSize(0)
+	r.SetLimits(Limits{LineLength: -1})
+	if _, err := r.ReadSession(nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkReader_Decode(b *testing.B) {
+	data := loadData(b, "sdp_session_ex_full", testCRNL)
+	src := bytes.NewReader(data)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		src.Reset(data)
+		if err := NewReader(src).Decode(new(Message)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
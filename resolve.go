@@ -0,0 +1,70 @@
+package sdp
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// Resolver looks up IP addresses of host, implemented by *net.Resolver.
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// ErrNoAddress means that host has no IP addresses of address type.
+var ErrNoAddress = errors.New("no addresses of address type")
+
+// resolve returns IP addresses of host that match addressType, using r
+// or net.DefaultResolver if r is nil.
+func resolve(ctx context.Context, r Resolver, host, addressType string) ([]net.IP, error) {
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	addrs, err := r.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve %s", host)
+	}
+	ips := make([]net.IP, 0, len(addrs))
+	for _, a := range addrs {
+		switch addressType {
+		case addrTypeIPv4:
+			if a.IP.To4() == nil {
+				continue
+			}
+		case addrTypeIPv6:
+			if a.IP.To4() != nil {
+				continue
+			}
+		}
+		ips = append(ips, a.IP)
+	}
+	if len(ips) == 0 {
+		return nil, errors.Wrapf(ErrNoAddress, "failed to resolve %s %s", addressType, host)
+	}
+	return ips, nil
+}
+
+// Resolve returns c.IP or IP addresses of c.Host that match address
+// type, using r or net.DefaultResolver if r is nil.
+func (c ConnectionData) Resolve(ctx context.Context, r Resolver) ([]net.IP, error) {
+	if len(c.IP) > 0 {
+		return []net.IP{c.IP}, nil
+	}
+	if c.Address != nil {
+		return nil, errors.Wrapf(ErrNoAddress, "%s network", c.NetworkType)
+	}
+	if len(c.Host) == 0 {
+		return nil, errors.Wrap(ErrNoAddress, "blank connection data")
+	}
+	return resolve(ctx, r, c.Host, c.getAddressType())
+}
+
+// Resolve returns IP of o.Address or IP addresses of o.Address host
+// that match address type, using r or net.DefaultResolver if r is nil.
+func (o *Origin) Resolve(ctx context.Context, r Resolver) ([]net.IP, error) {
+	if ip := net.ParseIP(o.Address); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	return resolve(ctx, r, o.Address, o.getAddressType())
+}
@@ -0,0 +1,148 @@
+package sdp
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+type resolverFunc func(ctx context.Context, host string) ([]net.IPAddr, error)
+
+func (f resolverFunc) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return f(ctx, host)
+}
+
+var testResolver = resolverFunc(func(ctx context.Context, host string) ([]net.IPAddr, error) {
+	switch host {
+	case "media.example.com", "host.example.com":
+		return []net.IPAddr{
+			{IP: net.ParseIP("2001:db8::1")},
+			{IP: net.ParseIP("192.0.2.1")},
+		}, nil
+	case "media6.example.com":
+		return []net.IPAddr{{IP: net.ParseIP("2001:db8::2")}}, nil
+	default:
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+})
+
+func TestDecoder_FQDN(t *testing.T) {
+	data := loadData(t, "sdp_session_ex_fqdn", testCRNL)
+	m, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Origin.Address != "host.example.com" || m.Origin.AddressType != "IP4" {
+		t.Errorf("unexpected origin %+v", m.Origin)
+	}
+	if m.Connection.Host != "media.example.com" || len(m.Connection.IP) != 0 {
+		t.Errorf("unexpected connection %+v", m.Connection)
+	}
+	if m.Medias[0].Connections[0].Host != "media6.example.com" {
+		t.Errorf("unexpected media connection %+v", m.Medias[0].Connections[0])
+	}
+	if m.Medias[1].Connections[0].Host != "" || m.Medias[1].Connections[0].TTL != 127 {
+		t.Errorf("unexpected media connection %+v", m.Medias[1].Connections[0])
+	}
+	t.Run("Encode", func(t *testing.T) {
+		out := m.Append(nil).AppendTo(nil)
+		if !bytes.Equal(bytes.TrimSpace(out), bytes.TrimSpace(data)) {
+			t.Errorf("%s != %s", out, data)
+		}
+	})
+	t.Run("Resolve", func(t *testing.T) {
+		ctx := context.Background()
+		ips, err := m.Connection.Resolve(ctx, testResolver)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ips) != 1 || !ips[0].Equal(net.ParseIP("192.0.2.1")) {
+			t.Errorf("unexpected %v", ips)
+		}
+		ips, err = m.Medias[0].Connections[0].Resolve(ctx, testResolver)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ips) != 1 || !ips[0].Equal(net.ParseIP("2001:db8::2")) {
+			t.Errorf("unexpected %v", ips)
+		}
+		ips, err = m.Medias[1].Connections[0].Resolve(ctx, testResolver)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ips) != 1 || !ips[0].Equal(net.ParseIP("224.2.1.1")) {
+			t.Errorf("unexpected %v", ips)
+		}
+		ips, err = m.Origin.Resolve(ctx, testResolver)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ips) != 1 || !ips[0].Equal(net.ParseIP("192.0.2.1")) {
+			t.Errorf("unexpected %v", ips)
+		}
+	})
+}
+
+func TestConnectionData_Resolve(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name string
+		c    ConnectionData
+		err  error
+	}{
+		{"Blank", ConnectionData{}, ErrNoAddress},
+		{"NoIP4", ConnectionData{Host: "media6.example.com"}, ErrNoAddress},
+		{"NotFound", ConnectionData{Host: "unknown.example.com"}, &net.DNSError{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.c.Resolve(ctx, testResolver)
+			if err == nil {
+				t.Fatal("should fail")
+			}
+			if dnsErr, ok := tc.err.(*net.DNSError); ok {
+				if !errors.As(err, &dnsErr) {
+					t.Errorf("unexpected error %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.err) {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+	t.Run("IP6", func(t *testing.T) {
+		c := ConnectionData{AddressType: "IP6", Host: "host.example.com"}
+		ips, err := c.Resolve(ctx, testResolver)
+		if err != nil || len(ips) != 1 || !ips[0].Equal(net.ParseIP("2001:db8::1")) {
+			t.Errorf("unexpected %v, %v", ips, err)
+		}
+	})
+	t.Run("Origin", func(t *testing.T) {
+		o := Origin{Address: "10.47.16.5"}
+		ips, err := o.Resolve(ctx, testResolver)
+		if err != nil || len(ips) != 1 || !ips[0].Equal(net.ParseIP("10.47.16.5")) {
+			t.Errorf("unexpected %v, %v", ips, err)
+		}
+	})
+}
+
+func TestIsFQDN(t *testing.T) {
+	for _, tc := range []struct {
+		in string
+		ok bool
+	}{
+		{"media.example.com", true},
+		{"host-1", true},
+		{"bad", false},
+		{"10.0.0.256", false},
+		{"2001:db8::1", false},
+		{"host_name", false},
+	} {
+		if isFQDN([]byte(tc.in)) != tc.ok {
+			t.Errorf("isFQDN(%q) != %v", tc.in, tc.ok)
+		}
+	}
+}
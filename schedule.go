@@ -0,0 +1,143 @@
+package sdp
+
+import (
+	"sort"
+	"time"
+)
+
+// Occurrence is time interval when session is active. Zero End means
+// that session is not bounded, and zero Start with zero End means
+// that session is permanent.
+type Occurrence struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains returns true if t is in [o.Start, o.End) interval.
+func (o Occurrence) Contains(t time.Time) bool {
+	if t.Before(o.Start) {
+		return false
+	}
+	return o.End.IsZero() || t.Before(o.End)
+}
+
+// overlaps returns true if o overlaps [from, to) interval.
+func (o Occurrence) overlaps(from, to time.Time) bool {
+	if !o.Start.Before(to) {
+		return false
+	}
+	return o.End.IsZero() || o.End.After(from)
+}
+
+// MaxOccurrences is maximum count of occurrences that are returned
+// for repeat times of single time description, so "r=1s" over wide
+// interval can't exhaust memory.
+const MaxOccurrences = 4096
+
+// adjust returns t shifted by offset of last time zone adjustment
+// that starts before t, see RFC 4566 Section 5.11.
+func adjust(zones []TimeZone, t time.Time) time.Time {
+	var offset time.Duration
+	for _, z := range zones {
+		if z.Start.After(t) {
+			break
+		}
+		offset = z.Offset
+	}
+	return t.Add(offset)
+}
+
+// occurrences appends occurrences of t that overlap [from, to) to o,
+// failing with *LimitError after MaxOccurrences of them.
+func (t Timing) occurrences(o []Occurrence, zones []TimeZone, from, to time.Time) ([]Occurrence, error) {
+	if t.Start.IsZero() {
+		// Permanent or unbounded session.
+		occurrence := Occurrence{End: t.End}
+		if occurrence.overlaps(from, to) {
+			o = append(o, occurrence)
+		}
+		return o, nil
+	}
+	if t.Repeat <= 0 || len(t.Offsets) == 0 {
+		occurrence := Occurrence{Start: t.Start, End: t.End}
+		if occurrence.overlaps(from, to) {
+			o = append(o, occurrence)
+		}
+		return o, nil
+	}
+	var maxOffset time.Duration
+	for _, offset := range t.Offsets {
+		if offset > maxOffset {
+			maxOffset = offset
+		}
+	}
+	// Skipping periods that end before from. Adjustments are not
+	// taken into account here, so one more period is checked.
+	var period int64
+	if skip := from.Sub(t.Start) - maxOffset - t.Active; skip > 0 {
+		period = int64(skip/t.Repeat) - 1
+		if period < 0 {
+			period = 0
+		}
+	}
+	for appended := 0; ; period++ {
+		base := t.Start.Add(time.Duration(period) * t.Repeat)
+		if !t.End.IsZero() && !base.Before(t.End) {
+			return o, nil
+		}
+		if adjust(zones, base).After(to) {
+			return o, nil
+		}
+		for _, offset := range t.Offsets {
+			start := base.Add(offset)
+			if !t.End.IsZero() && !start.Before(t.End) {
+				continue
+			}
+			start = adjust(zones, start)
+			occurrence := Occurrence{Start: start, End: start.Add(t.Active)}
+			if !occurrence.overlaps(from, to) {
+				continue
+			}
+			if appended == MaxOccurrences {
+				return o, &LimitError{Limit: "Occurrences", Max: MaxOccurrences}
+			}
+			o = append(o, occurrence)
+			appended++
+		}
+	}
+}
+
+// Occurrences returns intervals when session is active that overlap
+// [from, to) interval, sorted by start. Repeat times are expanded
+// and adjusted by TZAdjustments as described in RFC 4566 Section 5.11.
+// If time description has more than MaxOccurrences of them, first
+// MaxOccurrences are returned with *LimitError.
+func (m *Message) Occurrences(from, to time.Time) ([]Occurrence, error) {
+	var (
+		o   []Occurrence
+		err error
+	)
+	for _, t := range m.Timing {
+		var tErr error
+		if o, tErr = t.occurrences(o, m.TZAdjustments, from, to); tErr != nil && err == nil {
+			err = tErr
+		}
+	}
+	sort.SliceStable(o, func(i, j int) bool {
+		return o[i].Start.Before(o[j].Start)
+	})
+	return o, err
+}
+
+// ActiveAt returns true if session is active at t.
+func (m *Message) ActiveAt(t time.Time) bool {
+	// Truncated occurrences still contain t, because all of them
+	// overlap [t, t+1ns) interval.
+	occurrences, _ := m.Occurrences(t, t.Add(time.Nanosecond))
+	for _, o := range occurrences {
+		if o.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package sdp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestMessage_Occurrences(t *testing.T) {
+	start := time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC)
+	weekly := Timing{
+		Start:   start,
+		End:     start.Add(3 * 7 * 24 * time.Hour),
+		Repeat:  7 * 24 * time.Hour,
+		Active:  time.Hour,
+		Offsets: []time.Duration{0, 25 * time.Hour},
+	}
+	for _, tc := range []struct {
+		name     string
+		m        Message
+		from, to time.Time
+		expected []Occurrence
+	}{
+		{
+			name: "Permanent",
+			m:    Message{Timing: []Timing{{}}},
+			from: start, to: start.Add(time.Hour),
+			expected: []Occurrence{{}},
+		},
+		{
+			name: "PermanentEnded",
+			m:    Message{Timing: []Timing{{End: start}}},
+			from: start, to: start.Add(time.Hour),
+		},
+		{
+			name: "PermanentUntilEnd",
+			m:    Message{Timing: []Timing{{End: start.Add(time.Minute)}}},
+			from: start, to: start.Add(time.Hour),
+			expected: []Occurrence{{End: start.Add(time.Minute)}},
+		},
+		{
+			name: "Unbounded",
+			m:    Message{Timing: []Timing{{Start: start}}},
+			from: start, to: start.Add(time.Hour),
+			expected: []Occurrence{{Start: start}},
+		},
+		{
+			name: "Single",
+			m:    Message{Timing: []Timing{{Start: start, End: start.Add(time.Hour)}}},
+			from: start.Add(-time.Hour), to: start.Add(time.Minute),
+			expected: []Occurrence{{Start: start, End: start.Add(time.Hour)}},
+		},
+		{
+			name: "SingleBefore",
+			m:    Message{Timing: []Timing{{Start: start, End: start.Add(time.Hour)}}},
+			from: start.Add(-time.Hour), to: start,
+		},
+		{
+			name: "Repeat",
+			m:    Message{Timing: []Timing{weekly}},
+			from: start.Add(24 * time.Hour), to: start.Add(8 * 24 * time.Hour),
+			expected: []Occurrence{
+				{Start: start.Add(25 * time.Hour), End: start.Add(26 * time.Hour)},
+				{Start: start.Add(7 * 24 * time.Hour), End: start.Add(7*24*time.Hour + time.Hour)},
+			},
+		},
+		{
+			name: "RepeatUntilEnd",
+			m:    Message{Timing: []Timing{weekly}},
+			from: start.Add(14 * 24 * time.Hour), to: start.Add(100 * 24 * time.Hour),
+			expected: []Occurrence{
+				{Start: start.Add(14 * 24 * time.Hour), End: start.Add(14*24*time.Hour + time.Hour)},
+				{Start: start.Add(15*24*time.Hour + time.Hour), End: start.Add(15*24*time.Hour + 2*time.Hour)},
+			},
+		},
+		{
+			name: "Adjustments",
+			m: Message{
+				Timing: []Timing{weekly},
+				TZAdjustments: []TimeZone{
+					{Start: start.Add(3 * 24 * time.Hour), Offset: -time.Hour},
+				},
+			},
+			from: start, to: start.Add(8 * 24 * time.Hour),
+			expected: []Occurrence{
+				{Start: start, End: start.Add(time.Hour)},
+				{Start: start.Add(25 * time.Hour), End: start.Add(26 * time.Hour)},
+				{Start: start.Add(7*24*time.Hour - time.Hour), End: start.Add(7 * 24 * time.Hour)},
+			},
+		},
+		{
+			name: "Sorted",
+			m: Message{Timing: []Timing{
+				{Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour)},
+				{Start: start, End: start.Add(time.Hour)},
+			}},
+			from: start, to: start.Add(24 * time.Hour),
+			expected: []Occurrence{
+				{Start: start, End: start.Add(time.Hour)},
+				{Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour)},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			o, err := tc.m.Occurrences(tc.from, tc.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(o) != len(tc.expected) {
+				t.Fatalf("%v != %v", o, tc.expected)
+			}
+			for i := range o {
+				if !o[i].Start.Equal(tc.expected[i].Start) || !o[i].End.Equal(tc.expected[i].End) {
+					t.Errorf("[%d]: %v != %v", i, o[i], tc.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMessage_OccurrencesLimit(t *testing.T) {
+	start := time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC)
+	m := Message{Timing: []Timing{{
+		Start:   start,
+		Repeat:  time.Second,
+		Active:  time.Second,
+		Offsets: []time.Duration{0},
+	}}}
+	o, err := m.Occurrences(start, start.Add(365*24*time.Hour))
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(o) != MaxOccurrences {
+		t.Fatalf("unexpected count %d", len(o))
+	}
+	if last := o[len(o)-1].Start; !last.Equal(start.Add((MaxOccurrences - 1) * time.Second)) {
+		t.Errorf("unexpected last occurrence %s", last)
+	}
+	t.Run("Skipped", func(t *testing.T) {
+		// Occurrences of first offset end before from and are not
+		// counted.
+		m.Timing[0].Offsets = []time.Duration{0, 24 * time.Hour}
+		from := start.Add(24 * time.Hour)
+		o, err := m.Occurrences(from, from.Add(time.Hour))
+		if !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("unexpected error %v", err)
+		}
+		if len(o) != MaxOccurrences || !o[0].Start.Equal(from) {
+			t.Fatalf("unexpected count %d", len(o))
+		}
+	})
+	t.Run("Exact", func(t *testing.T) {
+		m.Timing[0].Offsets = []time.Duration{0}
+		o, err := m.Occurrences(start, start.Add(MaxOccurrences*time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(o) != MaxOccurrences {
+			t.Fatalf("unexpected count %d", len(o))
+		}
+	})
+}
+
+func TestMessage_ActiveAt(t *testing.T) {
+	tData := loadData(t, "sdp_session_ex_full", testNL)
+	s, err := DecodeSession(tData, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := new(Message)
+	d := NewDecoder(s)
+	if err = d.Decode(m); err != nil {
+		t.Fatal(err)
+	}
+	// t=2873397496 2873404696 is shorter than repeat interval, so only
+	// first hour is active.
+	start := NTPToTime(2873397496)
+	for _, tc := range []struct {
+		t      time.Time
+		active bool
+	}{
+		{start.Add(-time.Minute), false},
+		{start, true},
+		{start.Add(59 * time.Minute), true},
+		{start.Add(time.Hour), false},
+		{start.Add(25 * time.Hour), false},
+	} {
+		if m.ActiveAt(tc.t) != tc.active {
+			t.Errorf("ActiveAt(%s) != %v", tc.t, tc.active)
+		}
+	}
+	permanent := &Message{Timing: []Timing{{}}}
+	if !permanent.ActiveAt(start) {
+		t.Error("permanent session should be active")
+	}
+}
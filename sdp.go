@@ -77,7 +77,24 @@ func (l *Line) Decode(b []byte) error {
 }
 
 func (l Line) String() string {
-	return fmt.Sprintf("%s: %s", l.Type, string(l.Value))
+	return fmt.Sprintf("%s: %s", l.Type, l.redactedValue())
+}
+
+// GoString implements fmt.GoStringer, redacting encryption key.
+func (l Line) GoString() string {
+	return fmt.Sprintf("sdp.Line{Type:%q, Value:[]byte(%q)}", rune(l.Type), l.redactedValue())
+}
+
+// redactedValue returns value of line with key of "k=" line replaced,
+// so keys don't leak to logs. See Encryption.String.
+func (l Line) redactedValue() string {
+	if l.Type != TypeEncryptionKey {
+		return string(l.Value)
+	}
+	if i := bytes.IndexByte(l.Value, ':'); i >= 0 {
+		return string(l.Value[:i+1]) + redacted
+	}
+	return string(l.Value)
 }
 
 func appendCLRF(b []byte) []byte {
@@ -165,11 +182,7 @@ func (s Session) reset() Session {
 
 // AppendTo appends all session lines to b and returns b.
 func (s Session) AppendTo(b []byte) []byte {
-	for _, l := range s {
-		b = l.AppendTo(b)
-		b = appendCLRF(b)
-	}
-	return b
+	return EncodeOptions{}.AppendSession(b, s)
 }
 
 // Equal returns true if b == s.
@@ -185,6 +198,21 @@ func (s Session) Equal(b Session) bool {
 	return true
 }
 
+// clone returns deep copy of s.
+func (s Session) clone() Session {
+	if s == nil {
+		return nil
+	}
+	c := make(Session, len(s))
+	for i, l := range s {
+		c[i] = Line{
+			Type:  l.Type,
+			Value: append([]byte(nil), l.Value...),
+		}
+	}
+	return c
+}
+
 func (s Session) getLine(t Type) Line {
 	line := Line{
 		Type: t,
@@ -255,28 +283,27 @@ func (s *sliceScanner) Scan() bool {
 //
 // If s is passed, it will be reused with its lines.
 // It is safe to mutate b.
+//
+// DefaultLimits are applied, see Limits.DecodeSession.
 func DecodeSession(b []byte, s Session) (Session, error) {
-	var (
-		line Line
-		err  error
-	)
-	scanner := newScanner(b)
-	for scanner.Scan() {
-		// trying to reuse some memory
-		l := len(s)
-		if cap(s) > l+1 {
-			// picking element from s that is not in
-			// slice bounds, but in underlying array
-			// and reusing it byte slice
-			line.Value = s[:l+1][l].Value[:0]
-		}
-		if err = line.Decode(scanner.Line()); err != nil {
-			break
-		}
-		s = append(s, line)
-		line.Value = nil // not corrupting.
+	return Limits{}.DecodeSession(b, s)
+}
+
+// appendDecoded decodes b as Line and appends it to s.
+func (s Session) appendDecoded(b []byte) (Session, error) {
+	var line Line
+	// trying to reuse some memory
+	l := len(s)
+	if cap(s) > l+1 {
+		// picking element from s that is not in
+		// slice bounds, but in underlying array
+		// and reusing it byte slice
+		line.Value = s[:l+1][l].Value[:0]
+	}
+	if err := line.Decode(b); err != nil {
+		return s, err
 	}
-	return s, err
+	return append(s, line), nil
 }
 
 // Decode decodes b as SDP message, returning error if any.
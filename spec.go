@@ -0,0 +1,85 @@
+package sdp
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// Spec is specification that is followed by Decoder and EncodeOptions.
+type Spec int
+
+// Supported specifications.
+const (
+	// RFC4566 is SDP: Session Description Protocol (2006), default.
+	RFC4566 Spec = iota
+	// RFC8866 is SDP: Session Description Protocol (2021) that
+	// obsoletes RFC 4566. Time zone adjustments are part of time
+	// description, "k=" field is obsolete, attribute names are tokens
+	// and byte-strings can't contain NUL.
+	RFC8866
+)
+
+func (s Spec) String() string {
+	switch s {
+	case RFC4566:
+		return "RFC 4566"
+	case RFC8866:
+		return "RFC 8866"
+	default:
+		return "spec " + strconv.Itoa(int(s))
+	}
+}
+
+// isTokenChar returns true if c is allowed in token.
+//
+// See RFC 8866 Section 9:
+//
+//	token-char = %x21 / %x23-27 / %x2A-2B / %x2D-2E /
+//	             %x30-39 / %x41-5A / %x5E-7E
+func isTokenChar(c byte) bool {
+	switch {
+	case c == 0x21,
+		c >= 0x23 && c <= 0x27,
+		c == 0x2A || c == 0x2B,
+		c == 0x2D || c == 0x2E,
+		c >= 0x30 && c <= 0x39,
+		c >= 0x41 && c <= 0x5A,
+		c >= 0x5E && c <= 0x7E:
+		return true
+	default:
+		return false
+	}
+}
+
+// tokenError returns offset of first invalid character of token b
+// or -1 if b is valid token.
+func tokenError(b []byte) int {
+	if len(b) == 0 {
+		return 0
+	}
+	for i, c := range b {
+		if !isTokenChar(c) {
+			return i
+		}
+	}
+	return -1
+}
+
+// byteStringError returns offset of first invalid character of
+// byte-string b or -1 if b is valid byte-string.
+//
+// See RFC 8866 Section 9:
+//
+//	byte-string = 1*(%x01-09/%x0B-0C/%x0E-FF)
+func byteStringError(b []byte) int {
+	if len(b) == 0 {
+		return 0
+	}
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		return i
+	}
+	if i := bytes.IndexAny(b, "\r\n"); i >= 0 {
+		return i
+	}
+	return -1
+}
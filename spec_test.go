@@ -0,0 +1,256 @@
+package sdp
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestSpec_String(t *testing.T) {
+	for _, tc := range []struct {
+		spec Spec
+		out  string
+	}{
+		{RFC4566, "RFC 4566"},
+		{RFC8866, "RFC 8866"},
+		{Spec(10), "spec 10"},
+	} {
+		if v := tc.spec.String(); v != tc.out {
+			t.Errorf("%q != %q", v, tc.out)
+		}
+	}
+}
+
+func TestGrammar(t *testing.T) {
+	for _, tc := range []struct {
+		in         string
+		token      int
+		byteString int
+	}{
+		{"rtpmap", -1, -1},
+		{"", 0, 0},
+		{"x y", 1, -1},
+		{"a\x00", 1, 1},
+		{"ab\r", 2, 2},
+		{"a:b", 1, -1},
+		{"ü", 0, -1},
+	} {
+		if v := tokenError([]byte(tc.in)); v != tc.token {
+			t.Errorf("tokenError(%q) %d != %d", tc.in, v, tc.token)
+		}
+		if v := byteStringError([]byte(tc.in)); v != tc.byteString {
+			t.Errorf("byteStringError(%q) %d != %d", tc.in, v, tc.byteString)
+		}
+	}
+}
+
+func decodeSpec(tb testing.TB, data []byte, spec Spec) (*Message, Decoder, error) {
+	tb.Helper()
+	s, err := DecodeSession(data, nil)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	d := NewDecoderWithOptions(s, DecoderOptions{Spec: spec})
+	m := new(Message)
+	return m, d, d.Decode(m)
+}
+
+func TestDecoder_RFC8866(t *testing.T) {
+	t.Run("Example", func(t *testing.T) {
+		data := loadData(t, "rfc8866_ex1", testCRNL)
+		m, d, err := decodeSpec(t, data, RFC8866)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(d.Warnings()) != 0 {
+			t.Errorf("unexpected warnings: %v", d.Warnings())
+		}
+		if len(m.Medias) != 3 || m.Medias[2].Connections[0].AddressType != "IP6" {
+			t.Errorf("unexpected medias: %+v", m.Medias)
+		}
+		b, err := EncodeOptions{Spec: RFC8866}.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// IPv6 addresses are encoded in upper case.
+		if !bytes.EqualFold(b, data) {
+			t.Errorf("%q != %q", b, data)
+		}
+	})
+	t.Run("TimeZones", func(t *testing.T) {
+		data := loadData(t, "rfc8866_ex_zones", testNL)
+		m, d, err := decodeSpec(t, data, RFC8866)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(m.TZAdjustments) != 2 || m.TZAdjustments[0].Offset != -time.Hour {
+			t.Errorf("unexpected adjustments: %v", m.TZAdjustments)
+		}
+		if m.Encryption.Method != "prompt" {
+			t.Error("encryption should be decoded")
+		}
+		warnings := d.Warnings()
+		if len(warnings) != 1 || !errors.Is(warnings[0], ErrObsoleteField) {
+			t.Fatalf("unexpected warnings: %v", warnings)
+		}
+		if warnings[0].Line != 8 {
+			t.Errorf("unexpected line %d", warnings[0].Line)
+		}
+		// RFC 4566: time-fields = 1*( time-field *(repeat-field) ) [zone-adjustments]
+		m, d, err = decodeSpec(t, data, RFC4566)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(m.TZAdjustments) != 2 || len(d.Warnings()) != 0 {
+			t.Errorf("unexpected adjustments %v or warnings %v", m.TZAdjustments, d.Warnings())
+		}
+	})
+	for _, tc := range []struct {
+		name   string
+		in     string
+		line   int
+		column int
+		target error
+	}{
+		{
+			name:   "ZonesBeforeTiming",
+			in:     "z=2882844526 -1h\nt=0 0\nr=7d 1h 0\n",
+			line:   4,
+			column: 3,
+			target: ErrUnexpectedField,
+		},
+		{
+			name:   "ZonesWithoutRepeat",
+			in:     "t=0 0\nz=2882844526 -1h\n",
+			line:   5,
+			column: 3,
+			target: ErrUnexpectedField,
+		},
+		{
+			name:   "MultipleZones",
+			in:     "t=0 0\nr=7d 1h 0\nz=2882844526 -1h\nz=2882844526 -1h\n",
+			line:   7,
+			column: 3,
+			target: ErrUnexpectedField,
+		},
+		{
+			name:   "AttributeName",
+			in:     "t=0 0\na=key[]:value\n",
+			line:   5,
+			column: 6,
+			target: ErrInvalidSyntax,
+		},
+		{
+			name:   "AttributeValue",
+			in:     "t=0 0\na=key:val\x00ue\n",
+			line:   5,
+			column: 10,
+			target: ErrInvalidSyntax,
+		},
+		{
+			name:   "Information",
+			in:     "i=\x00\nt=0 0\n",
+			line:   4,
+			column: 3,
+			target: ErrInvalidSyntax,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			in := "v=0\no=- 1 2 IN IP4 127.0.0.1\ns=-\n" + tc.in
+			_, _, err := decodeSpec(t, []byte(in), RFC8866)
+			if !errors.Is(err, tc.target) {
+				t.Fatalf("errors.Is(%v, %v) should be true", err, tc.target)
+			}
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatal("errors.As failed")
+			}
+			if fieldErr.Line != tc.line || fieldErr.Column != tc.column {
+				t.Errorf("position %d:%d != %d:%d",
+					fieldErr.Line, fieldErr.Column, tc.line, tc.column,
+				)
+			}
+		})
+	}
+}
+
+func TestEncodeOptions_RFC8866(t *testing.T) {
+	m := &Message{
+		Origin: Origin{Address: "127.0.0.1"},
+		Name:   "-",
+		Medias: []Media{
+			{
+				Description: MediaDescription{Type: "audio", Port: 1, Protocol: "RTP/AVP"},
+				Encryption:  Encryption{Method: "prompt"},
+			},
+		},
+	}
+	if _, err := (EncodeOptions{Spec: RFC8866}).Marshal(m); !errors.Is(err, ErrObsoleteField) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	m.Encryption = Encryption{Method: "clear", Key: "secret"}
+	if _, err := (EncodeOptions{Spec: RFC8866}).Marshal(m); !errors.Is(err, ErrObsoleteField) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	b, err := EncodeOptions{Spec: RFC8866, ForceEncryption: true}.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, []byte("k=clear:secret\r\n")) || !bytes.Contains(b, []byte("k=prompt\r\n")) {
+		t.Errorf("encryption keys not encoded: %q", b)
+	}
+	if _, err = (EncodeOptions{}).Marshal(m); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEncodeOptions_TimeZonesRoundTrip(t *testing.T) {
+	zones := []TimeZone{
+		{Start: NTPToTime(2882844526), Offset: -time.Hour},
+		{Start: NTPToTime(2898848070)},
+	}
+	for _, tc := range []struct {
+		name   string
+		spec   Spec
+		repeat bool
+		err    error
+	}{
+		{"RFC4566", RFC4566, true, nil},
+		{"RFC4566WithoutRepeat", RFC4566, false, nil},
+		{"RFC8866", RFC8866, true, nil},
+		{"RFC8866WithoutRepeat", RFC8866, false, ErrUnexpectedField},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Message{
+				Origin:        Origin{Username: "-", NetworkType: "IN", AddressType: "IP4", Address: "127.0.0.1"},
+				Name:          "-",
+				Timing:        []Timing{{Start: NTPToTime(3034423619), End: NTPToTime(3042462419)}},
+				TZAdjustments: zones,
+			}
+			if tc.repeat {
+				m.Timing[0].Repeat = 7 * 24 * time.Hour
+				m.Timing[0].Active = time.Hour
+				m.Timing[0].Offsets = []time.Duration{0, 25 * time.Hour}
+			}
+			b, err := EncodeOptions{Spec: tc.spec}.Marshal(m)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if tc.err != nil {
+				return
+			}
+			decoded, d, err := decodeSpec(t, b, tc.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(d.Warnings()) != 0 {
+				t.Errorf("unexpected warnings: %v", d.Warnings())
+			}
+			if !decoded.Equal(m) {
+				t.Errorf("%+v != %+v", decoded, m)
+			}
+		})
+	}
+}
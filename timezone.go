@@ -0,0 +1,195 @@
+package sdp
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrTimeZoneRange means that time zone adjustment can't be represented
+// by time.Location.
+var ErrTimeZoneRange = errors.New("time zone adjustment out of range")
+
+// Location returns *time.Location in which schedule of m is local,
+// so repeat times at fixed wall clock time of loc follow TZAdjustments.
+// See NewLocation.
+func (m *Message) Location() (*time.Location, error) {
+	return NewLocation("SDP", m.TZAdjustments)
+}
+
+// NewLocation returns *time.Location with provided name that has zero
+// offset before first of zones and offset that is negation of last
+// adjustment that starts not after t for any other t, so it is inverse
+// of TimeZones. For "z=2882844526 -1h" offset is +1h since 2882844526,
+// because shifting base time back by one hour is same as moving clock
+// one hour forward.
+func NewLocation(name string, zones []TimeZone) (*time.Location, error) {
+	sorted := make([]TimeZone, len(zones))
+	copy(sorted, zones)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+	var (
+		offsets = []int64{0}
+		times   = make([]int64, 0, len(sorted))
+		types   = make([]byte, 0, len(sorted))
+	)
+	for _, z := range sorted {
+		offset := -int64(z.Offset / time.Second)
+		if offset <= math.MinInt32 || offset > math.MaxInt32 {
+			return nil, errors.Wrapf(ErrTimeZoneRange, "offset %s", z.Offset)
+		}
+		i := 0
+		for i < len(offsets) && offsets[i] != offset {
+			i++
+		}
+		if i == len(offsets) {
+			offsets = append(offsets, offset)
+		}
+		if i > math.MaxUint8 {
+			return nil, errors.Wrap(ErrTimeZoneRange, "too many distinct offsets")
+		}
+		times = append(times, z.Start.Unix())
+		types = append(types, byte(i))
+	}
+	var (
+		zoneData = make([]byte, 0, len(offsets)*6)
+		abbrev   []byte
+	)
+	for _, offset := range offsets {
+		zoneData = appendUint32(zoneData, uint32(offset))
+		zoneData = append(zoneData, 0, byte(len(abbrev)))
+		abbrev = append(abbrev, zoneAbbreviation(name, offset)...)
+		abbrev = append(abbrev, 0)
+	}
+	if len(abbrev) > math.MaxUint8 {
+		return nil, errors.Wrap(ErrTimeZoneRange, "name is too long")
+	}
+	// See RFC 8536 for TZif format. Version 2 is used because starts
+	// can be after 2038, when 32-bit transition times of version 1 end,
+	// so version 1 block has only transitions that fit into 32 bits.
+	first := 0
+	for first < len(times) && times[first] < math.MinInt32 {
+		first++
+	}
+	last := first
+	for last < len(times) && times[last] <= math.MaxInt32 {
+		last++
+	}
+	data := appendTZifBlock(nil, times[first:last], types[first:last], zoneData, abbrev, false)
+	data = appendTZifBlock(data, times, types, zoneData, abbrev, true)
+	data = append(data, '\n', '\n') // no TZ string footer
+	return time.LoadLocationFromTZData(name, data)
+}
+
+// appendTZifBlock appends TZif header and data block without leap
+// seconds and indicators to b, using 64-bit transition times if is64.
+func appendTZifBlock(b []byte, times []int64, types, zoneData, abbrev []byte, is64 bool) []byte {
+	b = append(b, "TZif2"...)
+	b = append(b, make([]byte, 15)...) // reserved
+	b = appendUint32(b, 0)             // isutcnt
+	b = appendUint32(b, 0)             // isstdcnt
+	b = appendUint32(b, 0)             // leapcnt
+	b = appendUint32(b, uint32(len(types)))
+	b = appendUint32(b, uint32(len(zoneData)/6))
+	b = appendUint32(b, uint32(len(abbrev)))
+	for _, t := range times {
+		if is64 {
+			b = appendUint32(b, uint32(uint64(t)>>32))
+		}
+		b = appendUint32(b, uint32(t))
+	}
+	b = append(b, types...)
+	b = append(b, zoneData...)
+	return append(b, abbrev...)
+}
+
+// zoneAbbreviation returns name for zero offset and numeric
+// abbreviation like "+01" or "-0130" for others.
+func zoneAbbreviation(name string, offset int64) string {
+	if offset == 0 {
+		return name
+	}
+	sign := byte('+')
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	minutes := offset / 60
+	b := []byte{sign}
+	if minutes/60 < 10 {
+		b = append(b, '0')
+	}
+	b = strconv.AppendInt(b, minutes/60, 10)
+	if minutes%60 != 0 {
+		if minutes%60 < 10 {
+			b = append(b, '0')
+		}
+		b = strconv.AppendInt(b, minutes%60, 10)
+	}
+	return string(b)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// transitionStep is interval of offset checks in TimeZones. Real time
+// zones never change offset twice in such interval.
+const transitionStep = time.Hour
+
+// TimeZones returns adjustments for every offset change of loc in
+// [from, to) interval, relative to offset of loc at from. Adjustment
+// is base offset minus new offset, so switch from CET to CEST is -1h:
+// session that repeats at same wall clock time starts one hour earlier
+// in UTC. Use it with
+// Session.AddTimeZones or Message.TZAdjustments, so repeated sessions
+// that are scheduled in loc stay correct across daylight saving changes.
+func TimeZones(loc *time.Location, from, to time.Time) []TimeZone {
+	var (
+		zones    []TimeZone
+		base     = zoneOffset(from, loc)
+		previous = base
+	)
+	for t := from; t.Before(to); {
+		next := t.Add(transitionStep)
+		if next.After(to) {
+			next = to
+		}
+		offset := zoneOffset(next, loc)
+		if offset != previous {
+			start := findTransition(loc, t, next, previous)
+			if start.Before(to) {
+				zones = append(zones, TimeZone{
+					Start:  start,
+					Offset: time.Duration(base-offset) * time.Second,
+				})
+			}
+			previous = offset
+		}
+		t = next
+	}
+	return zones
+}
+
+func zoneOffset(t time.Time, loc *time.Location) int {
+	_, offset := t.In(loc).Zone()
+	return offset
+}
+
+// findTransition returns first second in (lo, hi] when offset of loc
+// is not equal to offset.
+func findTransition(loc *time.Location, lo, hi time.Time, offset int) time.Time {
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if zoneOffset(mid, loc) == offset {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi.Truncate(time.Second)
+}
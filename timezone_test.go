@@ -0,0 +1,203 @@
+package sdp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestNewLocation(t *testing.T) {
+	tData := loadData(t, "sdp_session_ex_full", testNL)
+	s, err := DecodeSession(tData, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := new(Message)
+	d := NewDecoder(s)
+	if err = d.Decode(m); err != nil {
+		t.Fatal(err)
+	}
+	// z=2882844526 -1h 2898848070 0
+	loc, err := m.Location()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		t      time.Time
+		offset int
+		name   string
+	}{
+		{NTPToTime(2882844525), 0, "SDP"},
+		{NTPToTime(2882844526), 3600, "+01"},
+		{NTPToTime(2898848069), 3600, "+01"},
+		{NTPToTime(2898848070), 0, "SDP"},
+		{NTPToTime(2898848070).Add(time.Hour * 24 * 365), 0, "SDP"},
+	} {
+		name, offset := tc.t.In(loc).Zone()
+		if offset != tc.offset || name != tc.name {
+			t.Errorf("%s: %s %d != %s %d", tc.t.UTC(), name, offset, tc.name, tc.offset)
+		}
+	}
+	t.Run("Empty", func(t *testing.T) {
+		loc, err := NewLocation("SDP", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, offset := time.Now().In(loc).Zone(); offset != 0 {
+			t.Error("unexpected offset", offset)
+		}
+	})
+	t.Run("Unsorted", func(t *testing.T) {
+		start := time.Unix(1000, 0)
+		loc, err := NewLocation("SDP", []TimeZone{
+			{Start: start.Add(time.Hour), Offset: 0},
+			{Start: start, Offset: 90 * time.Minute},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		name, offset := start.Add(time.Minute).In(loc).Zone()
+		if offset != -90*60 || name != "-0130" {
+			t.Error("unexpected zone", name, offset)
+		}
+	})
+	t.Run("After2038", func(t *testing.T) {
+		start := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+		loc, err := NewLocation("SDP", []TimeZone{
+			{Start: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Offset: -time.Hour},
+			{Start: start, Offset: -2 * time.Hour},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, offset := start.Add(-time.Second).In(loc).Zone(); offset != 3600 {
+			t.Error("unexpected offset", offset)
+		}
+		if _, offset := start.In(loc).Zone(); offset != 7200 {
+			t.Error("unexpected offset", offset)
+		}
+	})
+	t.Run("Range", func(t *testing.T) {
+		_, err := NewLocation("SDP", []TimeZone{
+			{Start: time.Unix(0, 0), Offset: 100 * 365 * 24 * time.Hour},
+		})
+		if !errors.Is(err, ErrTimeZoneRange) {
+			t.Error("unexpected error", err)
+		}
+	})
+}
+
+func TestTimeZones(t *testing.T) {
+	start := time.Date(2020, 3, 29, 1, 0, 0, 0, time.UTC)
+	end := time.Date(2020, 10, 25, 1, 0, 0, 0, time.UTC)
+	// Location with daylight saving time like Europe/Berlin that is
+	// available without tzdata.
+	loc, err := NewLocation("CET", []TimeZone{
+		{Start: time.Unix(0, 0), Offset: -time.Hour},
+		{Start: start, Offset: -2 * time.Hour},
+		{Start: end, Offset: -time.Hour},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name     string
+		loc      *time.Location
+		from, to time.Time
+		expected []TimeZone
+	}{
+		{
+			name: "Year",
+			loc:  loc,
+			from: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: []TimeZone{
+				{Start: start, Offset: -time.Hour},
+				{Start: end, Offset: 0},
+			},
+		},
+		{
+			name: "Summer",
+			loc:  loc,
+			from: start.Add(time.Minute),
+			to:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: []TimeZone{
+				{Start: end, Offset: time.Hour},
+			},
+		},
+		{
+			name: "UntilTransition",
+			loc:  loc,
+			from: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:   start,
+		},
+		{
+			name: "Fixed",
+			loc:  time.FixedZone("MSK", 3*60*60),
+			from: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			zones := TimeZones(tc.loc, tc.from, tc.to)
+			if !equalTimeZones(zones, tc.expected) {
+				t.Errorf("%v != %v", zones, tc.expected)
+			}
+		})
+	}
+	t.Run("Encode", func(t *testing.T) {
+		zones := TimeZones(loc, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+		s := new(Session).AddTimeZones(zones...)
+		expected := "3794432400 -1h 3812576400 0"
+		if v := string(s[0].Value); v != expected {
+			t.Errorf("%q != %q", v, expected)
+		}
+	})
+}
+
+func TestTimeZones_Occurrences(t *testing.T) {
+	// Weekly session at 10:00 Berlin time, which is 09:00 UTC in winter
+	// and 08:00 UTC in summer.
+	loc, err := NewLocation("CET", []TimeZone{
+		{Start: time.Unix(0, 0), Offset: -time.Hour},
+		{Start: time.Date(2020, 3, 29, 1, 0, 0, 0, time.UTC), Offset: -2 * time.Hour},
+		{Start: time.Date(2020, 10, 25, 1, 0, 0, 0, time.UTC), Offset: -time.Hour},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2020, 3, 16, 10, 0, 0, 0, loc)
+	end := time.Date(2020, 4, 13, 0, 0, 0, 0, loc)
+	s := new(Session).
+		AddVersion(0).
+		AddOrigin(Origin{Username: "-", NetworkType: "IN", AddressType: "IP4", Address: "127.0.0.1"}).
+		AddSessionName("-").
+		AddTiming(start, end).
+		AddRepeatTimesCompact(7*24*time.Hour, time.Hour, 0).
+		AddTimeZones(TimeZones(loc, start, end)...)
+	b := s.AppendTo(nil)
+	m, err := Decode(b)
+	if err != nil {
+		t.Fatalf("failed to decode %q: %v", b, err)
+	}
+	occurrences, err := m.Occurrences(start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(occurrences) != 4 {
+		t.Fatalf("unexpected occurrences: %v", occurrences)
+	}
+	for i, o := range occurrences {
+		local := o.Start.In(loc)
+		if local.Hour() != 10 || local.Minute() != 0 || local.Weekday() != time.Monday {
+			t.Errorf("occurrence %d starts at %s", i, local)
+		}
+	}
+	if v := occurrences[1].Start.UTC().Hour(); v != 9 {
+		t.Errorf("unexpected winter hour %d", v)
+	}
+	if v := occurrences[2].Start.UTC().Hour(); v != 8 {
+		t.Errorf("unexpected summer hour %d", v)
+	}
+}
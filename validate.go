@@ -0,0 +1,220 @@
+package sdp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Possible causes of ValidationError that can be checked with errors.Is,
+// along with ErrMissingField.
+var (
+	ErrInvalidByteString = errors.New("NUL, CR or LF in byte-string")
+	ErrInvalidToken      = errors.New("space or illegal character in token")
+)
+
+// ValidationError describes field of Message that can't be encoded
+// safely.
+type ValidationError struct {
+	Field string // path to field, like "Medias[1].Attributes[0].Value"
+	Err   error  // ErrMissingField, ErrInvalidByteString or ErrInvalidToken
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %v", e.Field, e.Err)
+}
+
+// Unwrap returns underlying error.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// validateByteString checks that s can't break line. See RFC 8866
+// Section 9, byte-string.
+func validateByteString(field, s string) error {
+	if strings.ContainsAny(s, "\x00\r\n") {
+		return &ValidationError{Field: field, Err: ErrInvalidByteString}
+	}
+	return nil
+}
+
+// validateToken checks that s is non-empty and can't break line or
+// shift subfields.
+func validateToken(field, s string) error {
+	if s == "" {
+		return &ValidationError{Field: field, Err: ErrMissingField}
+	}
+	if strings.ContainsAny(s, " \t\x00\r\n") {
+		return &ValidationError{Field: field, Err: ErrInvalidToken}
+	}
+	return nil
+}
+
+// validateOptionalToken is validateToken that allows blank s.
+func validateOptionalToken(field, s string) error {
+	if s == "" {
+		return nil
+	}
+	return validateToken(field, s)
+}
+
+func validateRequired(field, s string) error {
+	if s == "" {
+		return &ValidationError{Field: field, Err: ErrMissingField}
+	}
+	return validateByteString(field, s)
+}
+
+func validateAttributes(prefix string, attributes Attributes) error {
+	for i, a := range attributes {
+		field := prefix + "Attributes[" + strconv.Itoa(i) + "]"
+		if err := validateToken(field+".Key", a.Key); err != nil {
+			return err
+		}
+		if strings.IndexByte(a.Key, attributesDelimiter) >= 0 {
+			return &ValidationError{Field: field + ".Key", Err: ErrInvalidToken}
+		}
+		if err := validateByteString(field+".Value", a.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateAddresses(prefix string, addresses []Address) error {
+	for i, a := range addresses {
+		field := prefix + "[" + strconv.Itoa(i) + "]"
+		if err := validateRequired(field+".Address", a.Address); err != nil {
+			return err
+		}
+		if err := validateByteString(field+".Name", a.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateBandwidths(prefix string, bandwidths Bandwidths) error {
+	for i, b := range bandwidths {
+		field := prefix + "Bandwidths[" + strconv.Itoa(i) + "].Type"
+		if err := validateToken(field, string(b.Type)); err != nil {
+			return err
+		}
+		if strings.IndexByte(string(b.Type), attributesDelimiter) >= 0 {
+			return &ValidationError{Field: field, Err: ErrInvalidToken}
+		}
+	}
+	return nil
+}
+
+func validateEncryption(prefix string, e Encryption) error {
+	if e.Blank() {
+		return nil
+	}
+	if err := validateToken(prefix+"Encryption.Method", e.Method); err != nil {
+		return err
+	}
+	return validateByteString(prefix+"Encryption.Key", e.Key)
+}
+
+func validateConnection(field string, c ConnectionData) error {
+	if c.Address != nil {
+		return firstError(
+			validateToken(field+".NetworkType", c.NetworkType),
+			validateToken(field+".AddressType", c.AddressType),
+			validateToken(field+".Address", string(c.Address.AppendTo(nil))),
+		)
+	}
+	return firstError(
+		validateOptionalToken(field+".NetworkType", c.NetworkType),
+		validateOptionalToken(field+".AddressType", c.AddressType),
+		validateOptionalToken(field+".Host", c.Host),
+		validateMulticast(field, c),
+	)
+}
+
+// validateMulticast checks that TTL and number of addresses are in
+// range and that TTL is not set for IP6.
+func validateMulticast(field string, c ConnectionData) error {
+	switch {
+	case c.TTL < 0 || c.TTL > MaxTTL:
+		return &ValidationError{Field: field + ".TTL", Err: ErrInvalidNumber}
+	case c.TTL > 0 && len(c.IP) > 0 && !isIPv4(c.IP):
+		return &ValidationError{Field: field + ".TTL", Err: ErrUnexpectedField}
+	case c.Addresses < 0 || c.Addresses > MaxAddresses:
+		return &ValidationError{Field: field + ".Addresses", Err: ErrInvalidNumber}
+	default:
+		return nil
+	}
+}
+
+// Validate returns *ValidationError for first field of m that is
+// missing or has characters that would break SDP syntax on encoding,
+// like CRLF in attribute value.
+//
+// Use EncodeOptions.Validate to validate messages before encoding.
+func (m *Message) Validate() error {
+	err := firstError(
+		validateToken("Origin.Username", m.Origin.Username),
+		validateOptionalToken("Origin.NetworkType", m.Origin.NetworkType),
+		validateOptionalToken("Origin.AddressType", m.Origin.AddressType),
+		validateToken("Origin.Address", m.Origin.Address),
+		validateRequired("Name", m.Name),
+		validateByteString("Info", m.Info),
+		validateByteString("URI", m.URI),
+		validateAddresses("Emails", m.Emails),
+		validateAddresses("Phones", m.Phones),
+		validateConnection("Connection", m.Connection),
+		validateBandwidths("", m.Bandwidths),
+		validateEncryption("", m.Encryption),
+		validateAttributes("", m.Attributes),
+	)
+	if err != nil {
+		return err
+	}
+	for i := range m.Medias {
+		if err = m.Medias[i].validate("Medias[" + strconv.Itoa(i) + "]."); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Media) validate(prefix string) error {
+	if err := firstError(
+		validateToken(prefix+"Description.Type", m.Description.Type),
+		validateToken(prefix+"Description.Protocol", m.Description.Protocol),
+	); err != nil {
+		return err
+	}
+	for i, c := range m.Connections {
+		field := prefix + "Connections[" + strconv.Itoa(i) + "]"
+		if err := validateConnection(field, c); err != nil {
+			return err
+		}
+	}
+	for i, f := range m.Description.Formats {
+		field := prefix + "Description.Formats[" + strconv.Itoa(i) + "]"
+		if err := validateToken(field, f); err != nil {
+			return err
+		}
+	}
+	return firstError(
+		validateByteString(prefix+"Title", m.Title),
+		validateBandwidths(prefix, m.Bandwidths),
+		validateEncryption(prefix, m.Encryption),
+		validateAttributes(prefix, m.Attributes),
+	)
+}
+
+// firstError returns first non-nil error of errs.
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package sdp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func validMessage() *Message {
+	m := &Message{
+		Origin: Origin{
+			Username:       "jdoe",
+			SessionID:      2890844526,
+			SessionVersion: 2890842807,
+			Address:        "10.47.16.5",
+		},
+		Name: "SDP Seminar",
+		Info: "A Seminar on the session description protocol",
+		Medias: []Media{
+			{
+				Description: MediaDescription{
+					Type:     "audio",
+					Port:     49170,
+					Formats:  []string{"0"},
+					Protocol: "RTP/AVP",
+				},
+			},
+		},
+	}
+	m.AddFlag("recvonly")
+	m.Medias[0].AddAttribute("rtpmap", "0", "PCMU/8000")
+	return m
+}
+
+func TestMessage_Validate(t *testing.T) {
+	if err := validMessage().Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name   string
+		modify func(m *Message)
+		field  string
+		target error
+	}{
+		{
+			name:   "NoUsername",
+			modify: func(m *Message) { m.Origin.Username = "" },
+			field:  "Origin.Username",
+			target: ErrMissingField,
+		},
+		{
+			name:   "UsernameSpace",
+			modify: func(m *Message) { m.Origin.Username = "j doe" },
+			field:  "Origin.Username",
+			target: ErrInvalidToken,
+		},
+		{
+			name:   "NetworkType",
+			modify: func(m *Message) { m.Origin.NetworkType = "IN IN" },
+			field:  "Origin.NetworkType",
+			target: ErrInvalidToken,
+		},
+		{
+			name:   "NoAddress",
+			modify: func(m *Message) { m.Origin.Address = "" },
+			field:  "Origin.Address",
+			target: ErrMissingField,
+		},
+		{
+			name:   "NoName",
+			modify: func(m *Message) { m.Name = "" },
+			field:  "Name",
+			target: ErrMissingField,
+		},
+		{
+			name:   "NameNUL",
+			modify: func(m *Message) { m.Name = "SDP\x00" },
+			field:  "Name",
+			target: ErrInvalidByteString,
+		},
+		{
+			name:   "URI",
+			modify: func(m *Message) { m.URI = "http://example.com/\r\na=x" },
+			field:  "URI",
+			target: ErrInvalidByteString,
+		},
+		{
+			name: "NoEmail",
+			modify: func(m *Message) {
+				m.Emails = []Address{{Address: "j.doe@example.com"}, {Name: "Jane Doe"}}
+			},
+			field:  "Emails[1].Address",
+			target: ErrMissingField,
+		},
+		{
+			name:   "PhoneName",
+			modify: func(m *Message) { m.Phones = []Address{{Address: "+1", Name: "\r\n"}} },
+			field:  "Phones[0].Name",
+			target: ErrInvalidByteString,
+		},
+		{
+			name:   "Connection",
+			modify: func(m *Message) { m.Connection.AddressType = "IP4\n" },
+			field:  "Connection.AddressType",
+			target: ErrInvalidToken,
+		},
+		{
+			name: "BandwidthType",
+			modify: func(m *Message) {
+				m.Medias[0].Bandwidths = m.Medias[0].Bandwidths.Set("X:Y", 1)
+			},
+			field:  "Medias[0].Bandwidths[0].Type",
+			target: ErrInvalidToken,
+		},
+		{
+			name: "MediaConnection",
+			modify: func(m *Message) {
+				m.Medias[0].Connections = []ConnectionData{
+					{IP: net.ParseIP("224.2.1.1")},
+					{Host: "media example.com"},
+				}
+			},
+			field:  "Medias[0].Connections[1].Host",
+			target: ErrInvalidToken,
+		},
+		{
+			name: "IP6TTL",
+			modify: func(m *Message) {
+				m.Connection = ConnectionData{IP: net.ParseIP("FF15::101"), TTL: 127}
+			},
+			field:  "Connection.TTL",
+			target: ErrUnexpectedField,
+		},
+		{
+			name: "TTL",
+			modify: func(m *Message) {
+				m.Connection = ConnectionData{IP: net.ParseIP("224.2.1.1"), TTL: 256}
+			},
+			field:  "Connection.TTL",
+			target: ErrInvalidNumber,
+		},
+		{
+			name:   "EncryptionKey",
+			modify: func(m *Message) { m.Encryption = Encryption{Method: "clear", Key: "a\nb"} },
+			field:  "Encryption.Key",
+			target: ErrInvalidByteString,
+		},
+		{
+			name:   "AttributeKey",
+			modify: func(m *Message) { m.AddAttribute("a:b", "c") },
+			field:  "Attributes[1].Key",
+			target: ErrInvalidToken,
+		},
+		{
+			name:   "AttributeInjection",
+			modify: func(m *Message) { m.AddAttribute("tool", "x\r\nm=video 1 RTP/AVP 0") },
+			field:  "Attributes[1].Value",
+			target: ErrInvalidByteString,
+		},
+		{
+			name:   "MediaType",
+			modify: func(m *Message) { m.Medias[0].Description.Type = "audio video" },
+			field:  "Medias[0].Description.Type",
+			target: ErrInvalidToken,
+		},
+		{
+			name:   "NoProtocol",
+			modify: func(m *Message) { m.Medias[0].Description.Protocol = "" },
+			field:  "Medias[0].Description.Protocol",
+			target: ErrMissingField,
+		},
+		{
+			name:   "Format",
+			modify: func(m *Message) { m.Medias[0].Description.Formats[0] = "0 8" },
+			field:  "Medias[0].Description.Formats[0]",
+			target: ErrInvalidToken,
+		},
+		{
+			name:   "Title",
+			modify: func(m *Message) { m.Medias[0].Title = "\n" },
+			field:  "Medias[0].Title",
+			target: ErrInvalidByteString,
+		},
+		{
+			name:   "MediaAttribute",
+			modify: func(m *Message) { m.Medias[0].AddAttribute("fmtp", "0\x00") },
+			field:  "Medias[0].Attributes[1].Value",
+			target: ErrInvalidByteString,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := validMessage()
+			tc.modify(m)
+			err := m.Validate()
+			if !errors.Is(err, tc.target) {
+				t.Fatalf("errors.Is(%v, %v) should be true", err, tc.target)
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatal("errors.As failed")
+			}
+			if validationErr.Field != tc.field {
+				t.Errorf("field %q != %q", validationErr.Field, tc.field)
+			}
+			if _, err = (EncodeOptions{Validate: true}).Marshal(m); !errors.Is(err, tc.target) {
+				t.Errorf("encoding should fail with %v: %v", tc.target, err)
+			}
+		})
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := &ValidationError{Field: "Name", Err: ErrMissingField}
+	if v := err.Error(); v != "invalid Name: missing field" {
+		t.Errorf("unexpected %q", v)
+	}
+}
+
+func TestEncodeOptions_Validate(t *testing.T) {
+	m := validMessage()
+	b, err := EncodeOptions{Validate: true}.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Decode(b); err != nil {
+		t.Error(err)
+	}
+	m.Name = ""
+	if _, err = (EncodeOptions{}).Marshal(m); err != nil {
+		t.Error("validation should be disabled by default")
+	}
+}